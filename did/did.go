@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package did
+
+import "github.com/ontology-tech/ontlogin-sdk-go/modules"
+
+// DidProcessor is implemented once per DID chain ontlogin supports (see
+// SDKConfig.Chain). OntLoginSdk keeps one instance per chain and dispatches
+// to it once a ClientResponse's DID has been mapped to a chain by
+// OntLoginSdk.GetDIDChain.
+type DidProcessor interface {
+	// VerifySig verifies sig over data using the key at keyIndex on did.
+	VerifySig(did string, keyIndex int, data []byte, sig []byte) error
+	// VerifyPresentation verifies vp was issued to did and satisfies requiredTypes.
+	VerifyPresentation(did string, keyIndex int, vp string, requiredTypes []*modules.VCFilter) error
+	// GetCredentialJsons decodes presentation into the JSON credentialSubjects it carries.
+	GetCredentialJsons(presentation string) ([]string, error)
+	// Sign signs data with the key at keyIndex on did, returning the raw signature.
+	// It is used by OntLoginSdk.GenerateChallengeJWT and sdk/oidc to produce
+	// server-signed JWTs.
+	Sign(did string, keyIndex int, data []byte) ([]byte, error)
+	// PublicKey returns the public key at keyIndex on did, so callers can
+	// publish it (e.g. in a JWKS document) without holding chain-specific state.
+	PublicKey(did string, keyIndex int) (interface{}, error)
+	// Resolve resolves did into its Document. Implementations that back
+	// onto a chain typically build the Document from the same on-chain
+	// registry VerifySig/PublicKey already read. It lets a DidProcessor be
+	// registered directly into a did.Registry alongside the did:key,
+	// did:web and did:ion resolvers in this package.
+	Resolve(did string) (*Document, error)
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package did
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/mr-tron/base58"
+)
+
+// VerificationMethod is a single key entry of a Document, following the
+// W3C DID Core data model. Exactly one of the PublicKey* fields is set,
+// depending on how the DID method encodes key material.
+type VerificationMethod struct {
+	ID                 string                 `json:"id"`
+	Type               string                 `json:"type"`
+	Controller         string                 `json:"controller"`
+	PublicKeyMultibase string                 `json:"publicKeyMultibase,omitempty"`
+	PublicKeyBase58    string                 `json:"publicKeyBase58,omitempty"`
+	PublicKeyJwk       map[string]interface{} `json:"publicKeyJwk,omitempty"`
+}
+
+// Service is a single service endpoint entry of a Document.
+type Service struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// Document is the reduced W3C DID Document model ontlogin needs: enough to
+// locate a verificationMethod and turn it into a usable key, regardless of
+// which method (did:key, did:web, did:ion, did:ont, ...) produced it.
+type Document struct {
+	ID                 string                `json:"id"`
+	VerificationMethod []*VerificationMethod `json:"verificationMethod"`
+	Authentication     []string              `json:"authentication,omitempty"`
+	AssertionMethod    []string              `json:"assertionMethod,omitempty"`
+	Service            []*Service            `json:"service,omitempty"`
+}
+
+// VerificationMethodByID returns the verificationMethod entry whose id
+// matches verificationMethodID, which may be given either in full
+// ("did:method:id#fragment") or as just the fragment ("#fragment").
+func (d *Document) VerificationMethodByID(verificationMethodID string) (*VerificationMethod, error) {
+	fragment := verificationMethodID
+	if idx := strings.IndexByte(verificationMethodID, '#'); idx >= 0 {
+		fragment = verificationMethodID[idx:]
+	}
+	for _, vm := range d.VerificationMethod {
+		if vm.ID == verificationMethodID || strings.HasSuffix(vm.ID, fragment) {
+			return vm, nil
+		}
+	}
+	return nil, fmt.Errorf("did: no verificationMethod %q in document for %s", verificationMethodID, d.ID)
+}
+
+// PublicKey decodes the verification method's embedded key material into a
+// crypto.PublicKey usable by VerifySignature: *ecdsa.PublicKey for
+// EcdsaSecp256r1VerificationKey2019, ed25519.PublicKey for
+// Ed25519VerificationKey2018/2020.
+func (vm *VerificationMethod) PublicKey() (interface{}, error) {
+	if vm.PublicKeyJwk != nil {
+		return publicKeyFromJwk(vm.PublicKeyJwk)
+	}
+
+	raw, err := vm.publicKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+	switch vm.Type {
+	case "Ed25519VerificationKey2018", "Ed25519VerificationKey2020":
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("did: invalid ed25519 public key length %d", len(raw))
+		}
+		return ed25519.PublicKey(raw), nil
+	case "EcdsaSecp256r1VerificationKey2019", "JsonWebKey2020":
+		x, y := elliptic.Unmarshal(elliptic.P256(), raw)
+		if x == nil {
+			return nil, fmt.Errorf("did: invalid P-256 public key encoding")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("did: unsupported verificationMethod type %q", vm.Type)
+	}
+}
+
+// publicKeyFromJwk decodes a publicKeyJwk entry (EC P-256 or OKP Ed25519,
+// the two key types ontlogin's VerifySignature supports) into a
+// crypto.PublicKey.
+func publicKeyFromJwk(jwk map[string]interface{}) (interface{}, error) {
+	kty, _ := jwk["kty"].(string)
+	crv, _ := jwk["crv"].(string)
+	x, _ := jwk["x"].(string)
+
+	xBytes, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, fmt.Errorf("did: invalid jwk x coordinate:%s", err.Error())
+	}
+
+	switch {
+	case kty == "OKP" && crv == "Ed25519":
+		if len(xBytes) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("did: invalid ed25519 jwk x length %d", len(xBytes))
+		}
+		return ed25519.PublicKey(xBytes), nil
+	case kty == "EC" && crv == "P-256":
+		y, _ := jwk["y"].(string)
+		yBytes, err := base64.RawURLEncoding.DecodeString(y)
+		if err != nil {
+			return nil, fmt.Errorf("did: invalid jwk y coordinate:%s", err.Error())
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(xBytes),
+			Y:     new(big.Int).SetBytes(yBytes),
+		}, nil
+	default:
+		return nil, fmt.Errorf("did: unsupported jwk kty/crv %s/%s", kty, crv)
+	}
+}
+
+// publicKeyBytes decodes whichever PublicKey* encoding is present into raw
+// key bytes.
+func (vm *VerificationMethod) publicKeyBytes() ([]byte, error) {
+	switch {
+	case vm.PublicKeyMultibase != "":
+		return decodeMultibase(vm.PublicKeyMultibase)
+	case vm.PublicKeyBase58 != "":
+		return base58.Decode(vm.PublicKeyBase58)
+	default:
+		return nil, fmt.Errorf("did: verificationMethod %s has no recognized public key encoding", vm.ID)
+	}
+}
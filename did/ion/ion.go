@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package ion resolves sidetree long-form DIDs (as used by did:ion and
+// compatible methods) entirely offline: the long-form identifier carries a
+// base64url-encoded create operation in its ":initial-state" suffix, and
+// the Document is reconstructed from that operation's patches without ever
+// contacting a sidetree node.
+package ion
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ontology-tech/ontlogin-sdk-go/did"
+)
+
+// createOperation is the subset of a sidetree create operation's delta
+// Resolver needs: the patches that add public keys and services.
+type createOperation struct {
+	Delta struct {
+		Patches []patch `json:"patches"`
+	} `json:"delta"`
+}
+
+// patch is a single sidetree "add-public-keys" or "add-services" patch.
+type patch struct {
+	Action     string           `json:"action"`
+	PublicKeys []publicKeyPatch `json:"publicKeys,omitempty"`
+	Services   []servicePatch   `json:"services,omitempty"`
+}
+
+type publicKeyPatch struct {
+	ID           string                 `json:"id"`
+	Type         string                 `json:"type"`
+	PublicKeyJwk map[string]interface{} `json:"publicKeyJwk,omitempty"`
+	Purposes     []string               `json:"purposes,omitempty"`
+}
+
+type servicePatch struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// Resolver resolves sidetree long-form DIDs without contacting a node.
+type Resolver struct{}
+
+// NewResolver returns a sidetree long-form Resolver.
+func NewResolver() *Resolver {
+	return &Resolver{}
+}
+
+// Resolve reconstructs id's Document from the create operation embedded in
+// its long-form ":initial-state" suffix.
+func (r *Resolver) Resolve(id string) (*did.Document, error) {
+	idx := strings.LastIndex(id, ":")
+	if idx < 0 {
+		return nil, fmt.Errorf("did/ion: invalid long-form did %q", id)
+	}
+	shortFormDid, encodedState := id[:idx], id[idx+1:]
+
+	stateJSON, err := base64.RawURLEncoding.DecodeString(encodedState)
+	if err != nil {
+		return nil, fmt.Errorf("did/ion: decode initial-state failed:%s", err.Error())
+	}
+	var op createOperation
+	if err := json.Unmarshal(stateJSON, &op); err != nil {
+		return nil, fmt.Errorf("did/ion: unmarshal create operation failed:%s", err.Error())
+	}
+
+	doc := &did.Document{ID: shortFormDid}
+	for _, p := range op.Delta.Patches {
+		switch p.Action {
+		case "add-public-keys":
+			for _, pk := range p.PublicKeys {
+				vmID := shortFormDid + "#" + pk.ID
+				vm := &did.VerificationMethod{
+					ID:           vmID,
+					Type:         pk.Type,
+					Controller:   shortFormDid,
+					PublicKeyJwk: pk.PublicKeyJwk,
+				}
+				doc.VerificationMethod = append(doc.VerificationMethod, vm)
+				for _, purpose := range pk.Purposes {
+					switch purpose {
+					case "authentication":
+						doc.Authentication = append(doc.Authentication, vmID)
+					case "assertionMethod":
+						doc.AssertionMethod = append(doc.AssertionMethod, vmID)
+					}
+				}
+			}
+		case "add-services":
+			for _, svc := range p.Services {
+				doc.Service = append(doc.Service, &did.Service{
+					ID:              shortFormDid + "#" + svc.ID,
+					Type:            svc.Type,
+					ServiceEndpoint: svc.ServiceEndpoint,
+				})
+			}
+		}
+	}
+	if len(doc.VerificationMethod) == 0 {
+		return nil, fmt.Errorf("did/ion: create operation for %s adds no public keys", id)
+	}
+	return doc, nil
+}
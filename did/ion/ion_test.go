@@ -0,0 +1,68 @@
+package ion
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func longFormDid(t *testing.T, op createOperation) string {
+	t.Helper()
+	stateJSON, err := json.Marshal(op)
+	if err != nil {
+		t.Fatalf("marshal create operation: %v", err)
+	}
+	return "did:ion:EiShortForm:" + base64.RawURLEncoding.EncodeToString(stateJSON)
+}
+
+func TestResolverReconstructsDocument(t *testing.T) {
+	op := createOperation{}
+	op.Delta.Patches = []patch{{
+		Action: "add-public-keys",
+		PublicKeys: []publicKeyPatch{{
+			ID:           "key-1",
+			Type:         "EcdsaSecp256k1VerificationKey2019",
+			PublicKeyJwk: map[string]interface{}{"kty": "EC"},
+			Purposes:     []string{"authentication", "assertionMethod"},
+		}},
+	}, {
+		Action: "add-services",
+		Services: []servicePatch{{
+			ID:              "svc-1",
+			Type:            "LinkedDomains",
+			ServiceEndpoint: "https://example.com",
+		}},
+	}}
+	id := longFormDid(t, op)
+
+	doc, err := NewResolver().Resolve(id)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(doc.VerificationMethod) != 1 {
+		t.Fatalf("expected 1 verificationMethod, got %d", len(doc.VerificationMethod))
+	}
+	vmID := doc.VerificationMethod[0].ID
+	if len(doc.Authentication) != 1 || doc.Authentication[0] != vmID {
+		t.Fatalf("expected authentication to reference %s, got %v", vmID, doc.Authentication)
+	}
+	if len(doc.AssertionMethod) != 1 || doc.AssertionMethod[0] != vmID {
+		t.Fatalf("expected assertionMethod to reference %s, got %v", vmID, doc.AssertionMethod)
+	}
+	if len(doc.Service) != 1 || doc.Service[0].Type != "LinkedDomains" {
+		t.Fatalf("expected one LinkedDomains service, got %v", doc.Service)
+	}
+}
+
+func TestResolverRejectsOperationWithNoKeys(t *testing.T) {
+	id := longFormDid(t, createOperation{})
+	if _, err := NewResolver().Resolve(id); err == nil {
+		t.Fatal("expected an error for a create operation that adds no public keys")
+	}
+}
+
+func TestResolverRejectsInvalidInitialState(t *testing.T) {
+	if _, err := NewResolver().Resolve("did:ion:EiShortForm:not-base64url!!"); err == nil {
+		t.Fatal("expected an error for an undecodable initial-state suffix")
+	}
+}
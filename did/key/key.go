@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package key resolves did:key identifiers, whose public key is encoded
+// directly in the identifier (multibase, multicodec-prefixed), so
+// resolution never needs a network request.
+package key
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ontology-tech/ontlogin-sdk-go/did"
+)
+
+// Resolver resolves did:key identifiers.
+type Resolver struct{}
+
+// NewResolver returns a did:key Resolver.
+func NewResolver() *Resolver {
+	return &Resolver{}
+}
+
+// Resolve decodes id's multibase-encoded public key and synthesizes the
+// single-verificationMethod Document the did:key spec defines for it.
+func (r *Resolver) Resolve(id string) (*did.Document, error) {
+	method, err := did.Method(id)
+	if err != nil {
+		return nil, err
+	}
+	if method != "key" {
+		return nil, fmt.Errorf("did/key: not a did:key identifier: %s", id)
+	}
+	parts := strings.SplitN(id, ":", 3)
+	multibaseKey := parts[2]
+
+	_, vmType, err := did.DecodeMultibaseKey(multibaseKey)
+	if err != nil {
+		return nil, fmt.Errorf("did/key: %s", err.Error())
+	}
+
+	vmID := id + "#" + multibaseKey
+	vm := &did.VerificationMethod{
+		ID:                 vmID,
+		Type:               vmType,
+		Controller:         id,
+		PublicKeyMultibase: multibaseKey,
+	}
+	return &did.Document{
+		ID:                 id,
+		VerificationMethod: []*did.VerificationMethod{vm},
+		Authentication:     []string{vmID},
+		AssertionMethod:    []string{vmID},
+	}, nil
+}
@@ -0,0 +1,47 @@
+package key
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mr-tron/base58"
+)
+
+func TestResolverResolvesEd25519(t *testing.T) {
+	rawKey := bytes.Repeat([]byte{0x01}, 32)
+	multibaseKey := "z" + base58.Encode(append([]byte{0xed, 0x01}, rawKey...))
+	id := "did:key:" + multibaseKey
+
+	doc, err := NewResolver().Resolve(id)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if doc.ID != id {
+		t.Fatalf("expected document id %s, got %s", id, doc.ID)
+	}
+	if len(doc.VerificationMethod) != 1 {
+		t.Fatalf("expected exactly one verificationMethod, got %d", len(doc.VerificationMethod))
+	}
+	vm := doc.VerificationMethod[0]
+	if vm.Type != "Ed25519VerificationKey2020" {
+		t.Fatalf("expected Ed25519VerificationKey2020, got %s", vm.Type)
+	}
+	if vm.PublicKeyMultibase != multibaseKey {
+		t.Fatalf("expected publicKeyMultibase %s, got %s", multibaseKey, vm.PublicKeyMultibase)
+	}
+	if len(doc.Authentication) != 1 || doc.Authentication[0] != vm.ID {
+		t.Fatalf("expected authentication to reference %s, got %v", vm.ID, doc.Authentication)
+	}
+}
+
+func TestResolverRejectsOtherMethods(t *testing.T) {
+	if _, err := NewResolver().Resolve("did:web:example.com"); err == nil {
+		t.Fatal("expected an error for a non-did:key identifier")
+	}
+}
+
+func TestResolverRejectsUnrecognizedKey(t *testing.T) {
+	if _, err := NewResolver().Resolve("did:key:zGarbage"); err == nil {
+		t.Fatal("expected an error for an undecodable multibase key")
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package did
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/mr-tron/base58"
+)
+
+// multicodec prefixes this package knows how to strip, varint-encoded per
+// the multicodec table (https://github.com/multiformats/multicodec).
+var (
+	multicodecEd25519Pub = []byte{0xed, 0x01}
+	multicodecP256Pub    = []byte{0x80, 0x24}
+)
+
+// decodeMultibaseTransport decodes just the multibase transport encoding
+// (RFC: the first byte selects the base), without interpreting any
+// multicodec prefix the decoded bytes may carry.
+func decodeMultibaseTransport(value string) ([]byte, error) {
+	if len(value) == 0 {
+		return nil, fmt.Errorf("did: empty multibase value")
+	}
+
+	switch value[0] {
+	case 'z': // base58-btc
+		raw, err := base58.Decode(value[1:])
+		if err != nil {
+			return nil, fmt.Errorf("did: decode multibase value failed:%s", err.Error())
+		}
+		return raw, nil
+	case 'u': // base64url, unpadded
+		raw, err := base64.RawURLEncoding.DecodeString(value[1:])
+		if err != nil {
+			return nil, fmt.Errorf("did: decode multibase value failed:%s", err.Error())
+		}
+		return raw, nil
+	case 'b': // base32, lowercase, unpadded
+		raw, err := base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding).DecodeString(value[1:])
+		if err != nil {
+			return nil, fmt.Errorf("did: decode multibase value failed:%s", err.Error())
+		}
+		return raw, nil
+	default:
+		return nil, fmt.Errorf("did: unsupported multibase prefix %q", value[0:1])
+	}
+}
+
+// decodeMultibase decodes a multibase value and strips a recognized
+// multicodec prefix, returning the raw key bytes. Used when the
+// verificationMethod's Type already tells us what kind of key it is.
+func decodeMultibase(value string) ([]byte, error) {
+	raw, err := decodeMultibaseTransport(value)
+	if err != nil {
+		return nil, err
+	}
+	return stripMulticodecPrefix(raw), nil
+}
+
+// DecodeMultibaseKey decodes a multibase-encoded, multicodec-prefixed
+// public key, such as the identifier of a did:key DID or a
+// publicKeyMultibase value whose key type is not yet known, and returns the
+// raw key bytes together with the VerificationMethod Type the multicodec
+// prefix implies.
+func DecodeMultibaseKey(value string) (raw []byte, vmType string, err error) {
+	decoded, err := decodeMultibaseTransport(value)
+	if err != nil {
+		return nil, "", err
+	}
+	switch {
+	case bytesHasPrefix(decoded, multicodecEd25519Pub):
+		return decoded[len(multicodecEd25519Pub):], "Ed25519VerificationKey2020", nil
+	case bytesHasPrefix(decoded, multicodecP256Pub):
+		return decoded[len(multicodecP256Pub):], "EcdsaSecp256r1VerificationKey2019", nil
+	default:
+		return nil, "", fmt.Errorf("did: unrecognized multicodec prefix in %q", value)
+	}
+}
+
+// stripMulticodecPrefix removes a known multicodec prefix from raw, if
+// present, returning the bare key bytes either way.
+func stripMulticodecPrefix(raw []byte) []byte {
+	for _, prefix := range [][]byte{multicodecEd25519Pub, multicodecP256Pub} {
+		if len(raw) > len(prefix) && bytesHasPrefix(raw, prefix) {
+			return raw[len(prefix):]
+		}
+	}
+	return raw
+}
+
+func bytesHasPrefix(b, prefix []byte) bool {
+	if len(b) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if b[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package did
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mr-tron/base58"
+)
+
+func TestDecodeMultibaseKeyEd25519(t *testing.T) {
+	rawKey := bytes.Repeat([]byte{0x01}, 32)
+	encoded := "z" + base58.Encode(append(append([]byte{}, multicodecEd25519Pub...), rawKey...))
+
+	decoded, vmType, err := DecodeMultibaseKey(encoded)
+	if err != nil {
+		t.Fatalf("DecodeMultibaseKey: %v", err)
+	}
+	if vmType != "Ed25519VerificationKey2020" {
+		t.Fatalf("expected Ed25519VerificationKey2020, got %s", vmType)
+	}
+	if !bytes.Equal(decoded, rawKey) {
+		t.Fatalf("expected %x, got %x", rawKey, decoded)
+	}
+}
+
+func TestDecodeMultibaseKeyP256(t *testing.T) {
+	rawKey := bytes.Repeat([]byte{0x02}, 33)
+	encoded := "z" + base58.Encode(append(append([]byte{}, multicodecP256Pub...), rawKey...))
+
+	decoded, vmType, err := DecodeMultibaseKey(encoded)
+	if err != nil {
+		t.Fatalf("DecodeMultibaseKey: %v", err)
+	}
+	if vmType != "EcdsaSecp256r1VerificationKey2019" {
+		t.Fatalf("expected EcdsaSecp256r1VerificationKey2019, got %s", vmType)
+	}
+	if !bytes.Equal(decoded, rawKey) {
+		t.Fatalf("expected %x, got %x", rawKey, decoded)
+	}
+}
+
+func TestDecodeMultibaseKeyUnrecognizedPrefix(t *testing.T) {
+	encoded := "z" + base58.Encode([]byte{0x00, 0x00, 0x01, 0x02})
+	if _, _, err := DecodeMultibaseKey(encoded); err == nil {
+		t.Fatal("expected an error for an unrecognized multicodec prefix")
+	}
+}
+
+func TestDecodeMultibaseKeyUnsupportedBase(t *testing.T) {
+	if _, _, err := DecodeMultibaseKey("x123"); err == nil {
+		t.Fatal("expected an error for an unsupported multibase prefix")
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package did
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Resolver resolves a DID into its Document. Every DidProcessor
+// implementation also satisfies Resolver, since DidProcessor.Resolve has
+// the same signature; Registry treats the two interchangeably.
+type Resolver interface {
+	Resolve(did string) (*Document, error)
+}
+
+// Registry dispatches a DID to the Resolver registered for its method. It
+// generalizes ontlogin's earlier Chain -> DidProcessor map (which only
+// understood three-segment did:ont:* identifiers) to arbitrary DID methods.
+type Registry struct {
+	resolvers map[string]Resolver
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{resolvers: make(map[string]Resolver)}
+}
+
+// Register associates method (the segment after "did:") with resolver.
+// Registering a method a second time replaces its resolver.
+func (r *Registry) Register(method string, resolver Resolver) {
+	r.resolvers[method] = resolver
+}
+
+// Lookup returns the Resolver registered for method, if any, without
+// resolving a DID.
+func (r *Registry) Lookup(method string) (Resolver, bool) {
+	resolver, ok := r.resolvers[method]
+	return resolver, ok
+}
+
+// Resolve resolves did by dispatching to the Resolver registered for its method.
+func (r *Registry) Resolve(did string) (*Document, error) {
+	method, err := Method(did)
+	if err != nil {
+		return nil, err
+	}
+	resolver, ok := r.resolvers[method]
+	if !ok {
+		return nil, fmt.Errorf("did: no resolver registered for method %q", method)
+	}
+	return resolver.Resolve(did)
+}
+
+// Method extracts the method segment from a "did:<method>:<method-specific-id>" identifier.
+func Method(did string) (string, error) {
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) != 3 || parts[0] != "did" {
+		return "", fmt.Errorf("did: invalid did %q", did)
+	}
+	return parts[1], nil
+}
+
+// DidFromVerificationMethod splits a "did:method:id#fragment"
+// verificationMethod identifier into its bare DID.
+func DidFromVerificationMethod(verificationMethod string) (string, error) {
+	idx := strings.IndexByte(verificationMethod, '#')
+	if idx < 0 {
+		return "", fmt.Errorf("did: verificationMethod %q has no fragment", verificationMethod)
+	}
+	return verificationMethod[:idx], nil
+}
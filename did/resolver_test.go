@@ -0,0 +1,62 @@
+package did
+
+import "testing"
+
+func TestMethod(t *testing.T) {
+	method, err := Method("did:key:z123")
+	if err != nil {
+		t.Fatalf("Method: %v", err)
+	}
+	if method != "key" {
+		t.Fatalf("expected key, got %s", method)
+	}
+
+	if _, err := Method("not-a-did"); err == nil {
+		t.Fatal("expected an error for a malformed did")
+	}
+}
+
+func TestDidFromVerificationMethod(t *testing.T) {
+	holderDid, err := DidFromVerificationMethod("did:key:z123#z123")
+	if err != nil {
+		t.Fatalf("DidFromVerificationMethod: %v", err)
+	}
+	if holderDid != "did:key:z123" {
+		t.Fatalf("expected did:key:z123, got %s", holderDid)
+	}
+
+	if _, err := DidFromVerificationMethod("did:key:z123"); err == nil {
+		t.Fatal("expected an error for a verificationMethod with no fragment")
+	}
+}
+
+type stubResolver struct {
+	doc *Document
+	err error
+}
+
+func (s *stubResolver) Resolve(string) (*Document, error) {
+	return s.doc, s.err
+}
+
+func TestRegistryDispatchesByMethod(t *testing.T) {
+	registry := NewRegistry()
+	doc := &Document{ID: "did:key:z123"}
+	registry.Register("key", &stubResolver{doc: doc})
+
+	if _, ok := registry.Lookup("web"); ok {
+		t.Fatal("expected no resolver registered for web")
+	}
+
+	resolved, err := registry.Resolve("did:key:z123")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if resolved != doc {
+		t.Fatal("expected the registered resolver's document back")
+	}
+
+	if _, err := registry.Resolve("did:web:example.com"); err == nil {
+		t.Fatal("expected an error for an unregistered method")
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package did
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// VerifySignature verifies sig over data was produced by pub, as returned
+// by VerificationMethod.PublicKey. It is the method-agnostic counterpart of
+// DidProcessor.VerifySig, used once a verificationMethod has been resolved
+// through a Registry instead of a chain-specific processor; the expected
+// signature algorithm is implied by pub's concrete type, since each key
+// type VerificationMethod.PublicKey produces supports exactly one.
+func VerifySignature(pub interface{}, data, sig []byte) error {
+	switch key := pub.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, sig) {
+			return fmt.Errorf("did: signature verification failed")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if len(sig) != 64 {
+			return fmt.Errorf("did: ES256 signature must be 64 bytes, got %d", len(sig))
+		}
+		digest := sha256.Sum256(data)
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(key, digest[:], r, s) {
+			return fmt.Errorf("did: signature verification failed")
+		}
+		return nil
+	default:
+		return fmt.Errorf("did: unsupported public key type %T", pub)
+	}
+}
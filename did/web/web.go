@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package web resolves did:web identifiers by fetching the DID document
+// over HTTPS from the identifier's /.well-known/did.json (or path-scoped
+// equivalent), per the did:web method spec.
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ontology-tech/ontlogin-sdk-go/did"
+)
+
+// HTTPClient is the subset of *http.Client Resolver needs, so callers can
+// inject retries, tracing, or a mock transport in tests.
+type HTTPClient interface {
+	Get(url string) (*http.Response, error)
+}
+
+// Resolver resolves did:web identifiers over HTTPS, caching each document
+// for the cacheTTL given to NewResolver so repeated logins from the same holder don't refetch it.
+type Resolver struct {
+	client   HTTPClient
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	doc       *did.Document
+	expiresAt time.Time
+}
+
+// NewResolver returns a did:web Resolver. A nil client defaults to
+// http.DefaultClient; cacheTTL of 0 disables caching.
+func NewResolver(client HTTPClient, cacheTTL time.Duration) *Resolver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Resolver{client: client, cacheTTL: cacheTTL, cache: make(map[string]cacheEntry)}
+}
+
+// Resolve fetches id's DID document, per the did:web method spec: the
+// method-specific id is a domain (and optional colon-separated path),
+// which is percent-decoded and turned into an HTTPS URL ending in
+// /did.json, defaulting to /.well-known/did.json when no path was given.
+func (r *Resolver) Resolve(id string) (*did.Document, error) {
+	method, err := did.Method(id)
+	if err != nil {
+		return nil, err
+	}
+	if method != "web" {
+		return nil, fmt.Errorf("did/web: not a did:web identifier: %s", id)
+	}
+
+	if doc := r.fromCache(id); doc != nil {
+		return doc, nil
+	}
+
+	docURL, err := didWebURL(id)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Get(docURL)
+	if err != nil {
+		return nil, fmt.Errorf("did/web: fetch %s failed:%s", docURL, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("did/web: fetch %s returned status %d", docURL, resp.StatusCode)
+	}
+
+	var doc did.Document
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("did/web: decode document from %s failed:%s", docURL, err.Error())
+	}
+
+	r.store(id, &doc)
+	return &doc, nil
+}
+
+func (r *Resolver) fromCache(id string) *did.Document {
+	if r.cacheTTL == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.cache[id]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil
+	}
+	return entry.doc
+}
+
+func (r *Resolver) store(id string, doc *did.Document) {
+	if r.cacheTTL == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache[id] = cacheEntry{doc: doc, expiresAt: time.Now().Add(r.cacheTTL)}
+}
+
+// didWebURL converts a did:web identifier into the HTTPS URL its document
+// is published at.
+func didWebURL(id string) (string, error) {
+	parts := strings.SplitN(id, ":", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("did/web: invalid did %q", id)
+	}
+
+	segments := strings.Split(parts[2], ":")
+	for i, seg := range segments {
+		decoded, err := url.PathUnescape(seg)
+		if err != nil {
+			return "", fmt.Errorf("did/web: invalid path segment %q", seg)
+		}
+		segments[i] = decoded
+	}
+
+	host := segments[0]
+	if len(segments) == 1 {
+		return fmt.Sprintf("https://%s/.well-known/did.json", host), nil
+	}
+	return fmt.Sprintf("https://%s/%s/did.json", host, strings.Join(segments[1:], "/")), nil
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type stubClient struct {
+	calls int
+	body  string
+	err   error
+}
+
+func (c *stubClient) Get(url string) (*http.Response, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(c.body)),
+	}, nil
+}
+
+const sampleDoc = `{"id":"did:web:example.com","verificationMethod":[{"id":"did:web:example.com#key-1","type":"Ed25519VerificationKey2020","controller":"did:web:example.com"}]}`
+
+func TestResolverFetchesDocument(t *testing.T) {
+	client := &stubClient{body: sampleDoc}
+	resolver := NewResolver(client, time.Minute)
+
+	doc, err := resolver.Resolve("did:web:example.com")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if doc.ID != "did:web:example.com" {
+		t.Fatalf("expected did:web:example.com, got %s", doc.ID)
+	}
+	if client.calls != 1 {
+		t.Fatalf("expected 1 fetch, got %d", client.calls)
+	}
+}
+
+func TestResolverCachesDocument(t *testing.T) {
+	client := &stubClient{body: sampleDoc}
+	resolver := NewResolver(client, time.Minute)
+
+	if _, err := resolver.Resolve("did:web:example.com"); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if _, err := resolver.Resolve("did:web:example.com"); err != nil {
+		t.Fatalf("Resolve (cached): %v", err)
+	}
+	if client.calls != 1 {
+		t.Fatalf("expected the cached document to avoid a second fetch, got %d calls", client.calls)
+	}
+}
+
+func TestResolverRejectsOtherMethods(t *testing.T) {
+	resolver := NewResolver(&stubClient{body: sampleDoc}, 0)
+	if _, err := resolver.Resolve("did:key:z123"); err == nil {
+		t.Fatal("expected an error for a non-did:web identifier")
+	}
+}
+
+func TestDidWebURL(t *testing.T) {
+	cases := []struct {
+		id  string
+		url string
+	}{
+		{"did:web:example.com", "https://example.com/.well-known/did.json"},
+		{"did:web:example.com:path:to:did", "https://example.com/path/to/did/did.json"},
+	}
+	for _, c := range cases {
+		url, err := didWebURL(c.id)
+		if err != nil {
+			t.Fatalf("didWebURL(%s): %v", c.id, err)
+		}
+		if url != c.url {
+			t.Fatalf("didWebURL(%s): expected %s, got %s", c.id, c.url, url)
+		}
+	}
+}
+
+func TestResolverSurfacesFetchError(t *testing.T) {
+	resolver := NewResolver(&stubClient{err: fmt.Errorf("connection refused")}, 0)
+	if _, err := resolver.Resolve("did:web:example.com"); err == nil {
+		t.Fatal("expected the fetch error to surface")
+	}
+}
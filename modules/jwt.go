@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package modules
+
+// JOSEHeader is the minimal JWS header ontlogin needs to verify a compact
+// JWT: alg selects the signature algorithm (must be one of SDKConfig.Alg)
+// and kid is the DID verificationMethod that produced the signature.
+type JOSEHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// ClientResponseJWT is the claim set of the JWT/JWS counterpart of
+// ClientResponse, following the W3C VC-JWT encoding: iss is the holder DID,
+// aud is the server DID from ServerInfo, nonce is the server-issued
+// challenge nonce, and vp carries the presentation the holder submits.
+type ClientResponseJWT struct {
+	Iss                    string                  `json:"iss"`
+	Aud                    string                  `json:"aud"`
+	Nonce                  string                  `json:"nonce"`
+	Jti                    string                  `json:"jti,omitempty"`
+	Nbf                    int64                   `json:"nbf,omitempty"`
+	Exp                    int64                   `json:"exp,omitempty"`
+	VP                     *PresentationJWT        `json:"vp,omitempty"`
+	PresentationSubmission *PresentationSubmission `json:"presentation_submission,omitempty"`
+}
+
+// PresentationJWT is the `vp` claim carried by a ClientResponseJWT: a
+// verifiable presentation wrapping one or more credential JWTs, each of
+// which is itself verified against the holder DID before its declared
+// type is checked against VCFilters.
+type PresentationJWT struct {
+	Context              []string `json:"@context"`
+	Type                 []string `json:"type"`
+	VerifiableCredential []string `json:"verifiableCredential"`
+}
+
+// CredentialJWT is the claim set of a single VC-JWT referenced from
+// PresentationJWT.VerifiableCredential.
+type CredentialJWT struct {
+	Iss string          `json:"iss"`
+	Jti string          `json:"jti,omitempty"`
+	Nbf int64           `json:"nbf,omitempty"`
+	Exp int64           `json:"exp,omitempty"`
+	VC  CredentialClaim `json:"vc"`
+}
+
+// CredentialClaim is the `vc` object inside a CredentialJWT: its type is
+// what VCFilters are evaluated against, and its credentialSubject is what a
+// PresentationDefinition's fields are evaluated against.
+type CredentialClaim struct {
+	Context           []string               `json:"@context"`
+	Type              []string               `json:"type"`
+	CredentialSubject map[string]interface{} `json:"credentialSubject"`
+}
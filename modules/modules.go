@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package modules holds the wire types ontlogin's ClientHello/ServerHello/
+// ClientResponse challenge-response protocol is built on.
+package modules
+
+const (
+	SYS_VER = "1.0"
+
+	TYPE_CLIENT_HELLO    = "ClientHello"
+	TYPE_SERVER_HELLO    = "ServerHello"
+	TYPE_CLIENT_RESPONSE = "ClientResponse"
+
+	// ACTION_AUTHORIZATION requests the holder prove control of their DID
+	// and, if VCFilters/PresentationDefinitions names one, present a
+	// credential. ACTION_CERTIFICATION requests a credential be issued to
+	// the holder.
+	ACTION_AUTHORIZATION = 0
+	ACTION_CERTIFICATION = 1
+
+	ERR_WRONG_VERSION        = "unsupported protocol version"
+	ERR_TYPE_NOT_SUPPORTED   = "unsupported message type"
+	ERR_ACTION_NOT_SUPPORTED = "unsupported action"
+)
+
+// ServerInfo identifies the server side of the handshake: Did is the
+// server's own DID, used both as the `aud` of ClientResponseJWT and to
+// locate the signing key GenerateChallengeJWT and sdk/oidc sign with.
+type ServerInfo struct {
+	Name               string `json:"name"`
+	Url                string `json:"url"`
+	Did                string `json:"did"`
+	VerificationMethod string `json:"verificationMethod"`
+}
+
+// ServerInfoToSign is the subset of ServerInfo bound into a
+// ClientResponseMsg, so a holder's proof covers the server identity it
+// negotiated with without including fields (like VerificationMethod) that
+// have no bearing on that binding.
+type ServerInfoToSign struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+	Did  string `json:"did"`
+}
+
+// ClientHello is the client's opening message: Action selects what it is
+// asking the server to do (see ACTION_AUTHORIZATION/ACTION_CERTIFICATION).
+type ClientHello struct {
+	Ver    string `json:"ver"`
+	Type   string `json:"type"`
+	Action int    `json:"action"`
+}
+
+// ServerHello is GenerateChallenge's response to a ClientHello: Nonce is
+// the single-use challenge the client's proof must cover, Chain/Alg tell
+// the client which DID chains and signature algorithms the server accepts,
+// and VCFilters/PresentationDefinition (set for req.Action, if configured)
+// tell it what credential, if any, the response must carry.
+type ServerHello struct {
+	Ver                    string                  `json:"ver"`
+	Type                   string                  `json:"type"`
+	Server                 *ServerInfo             `json:"server"`
+	Nonce                  string                  `json:"nonce"`
+	Chain                  []string                `json:"chain"`
+	Alg                    []string                `json:"alg"`
+	VCFilters              []*VCFilter             `json:"vcFilters,omitempty"`
+	PresentationDefinition *PresentationDefinition `json:"presentationDefinition,omitempty"`
+}
+
+// VCFilter constrains one credential type a server will accept: Type
+// matches a credential's declared @type, and a non-empty TrustedIssuer
+// additionally restricts the set of issuer DIDs accepted for it.
+type VCFilter struct {
+	Type          string   `json:"type"`
+	TrustedIssuer []string `json:"trustedIssuer,omitempty"`
+}
+
+// Proof is the holder's signature over a ClientResponseMsg (or, inside a
+// presentation, an issuer's signature over a credential): Value is a hex
+// encoded signature, and VerificationMethod is the "did:method:id#fragment"
+// key that produced it.
+type Proof struct {
+	Type               string `json:"type,omitempty"`
+	Created            string `json:"created,omitempty"`
+	VerificationMethod string `json:"verificationMethod"`
+	Value              string `json:"value"`
+}
+
+// ClientResponse is the holder's answer to a ServerHello: Proof covers
+// ClientResponseMsg (binding Nonce and the negotiated ServerInfoToSign to
+// Did), and VPs carries zero or more chain-encoded verifiable
+// presentations satisfying the action's VCFilters/PresentationDefinition.
+type ClientResponse struct {
+	Ver                    string                  `json:"ver"`
+	Type                   string                  `json:"type"`
+	Did                    string                  `json:"did"`
+	Nonce                  string                  `json:"nonce"`
+	Proof                  *Proof                  `json:"proof"`
+	VPs                    []string                `json:"vps,omitempty"`
+	PresentationSubmission *PresentationSubmission `json:"presentationSubmission,omitempty"`
+}
+
+// ClientResponseMsg is the canonical payload a ClientResponse.Proof signs:
+// binding the negotiated Server identity and Nonce to Did stops a proof
+// produced for one server/challenge from being replayed against another.
+type ClientResponseMsg struct {
+	Type    string           `json:"type"`
+	Server  ServerInfoToSign `json:"server"`
+	Nonce   string           `json:"nonce"`
+	Did     string           `json:"did"`
+	Created string           `json:"created,omitempty"`
+}
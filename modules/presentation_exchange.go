@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package modules
+
+import "encoding/json"
+
+// PresentationDefinition is ontlogin's reduced model of a DIF Presentation
+// Exchange presentation_definition: it lets a server request specific
+// credential types with specific attribute constraints (instead of the
+// type-only matching VCFilter does), and embeds in ServerHello for the
+// action it was configured against in SDKConfig.PresentationDefinitions.
+type PresentationDefinition struct {
+	ID                     string                   `json:"id"`
+	Name                   string                   `json:"name,omitempty"`
+	Purpose                string                   `json:"purpose,omitempty"`
+	InputDescriptors       []*InputDescriptor       `json:"input_descriptors"`
+	SubmissionRequirements []*SubmissionRequirement `json:"submission_requirements,omitempty"`
+}
+
+// InputDescriptor requests a single credential satisfying Constraints.
+// Group associates the descriptor with the "from" groups its
+// SubmissionRequirements reference.
+type InputDescriptor struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name,omitempty"`
+	Purpose     string       `json:"purpose,omitempty"`
+	Group       []string     `json:"group,omitempty"`
+	Constraints *Constraints `json:"constraints"`
+}
+
+// Constraints lists the attribute checks a credential must satisfy.
+// LimitDisclosure == "required" asks the holder to submit only the
+// attributes Fields references, as an SD-JWT/BBS+ selective disclosure
+// rather than the full credential.
+type Constraints struct {
+	LimitDisclosure string   `json:"limit_disclosure,omitempty"`
+	Fields          []*Field `json:"fields"`
+}
+
+// Field names one or more candidate JSONPaths into a credentialSubject (the
+// first that resolves is used, per the Presentation Exchange spec) and an
+// optional JSON Schema Filter the resolved value must satisfy. A Field with
+// no match is an error unless Optional is set.
+type Field struct {
+	Path     []string        `json:"path"`
+	Purpose  string          `json:"purpose,omitempty"`
+	Optional bool            `json:"optional,omitempty"`
+	Filter   json.RawMessage `json:"filter,omitempty"`
+}
+
+// SubmissionRequirement constrains which, and how many, of a
+// PresentationDefinition's grouped InputDescriptors must be satisfied:
+// Rule "all" requires every descriptor in From, "pick" requires Count (or
+// between Min and Max) of them.
+type SubmissionRequirement struct {
+	Name  string `json:"name,omitempty"`
+	Rule  string `json:"rule"`
+	From  string `json:"from"`
+	Count int    `json:"count,omitempty"`
+	Min   int    `json:"min,omitempty"`
+	Max   int    `json:"max,omitempty"`
+}
+
+// PresentationSubmission is the holder's declaration of which credential in
+// its response satisfies which PresentationDefinition.InputDescriptor.
+type PresentationSubmission struct {
+	ID            string           `json:"id"`
+	DefinitionID  string           `json:"definition_id"`
+	DescriptorMap []*DescriptorMap `json:"descriptor_map"`
+}
+
+// DescriptorMap points one InputDescriptor.ID at the credential that
+// satisfies it. Path addresses the credential's position among the
+// credentials the holder submitted, e.g. "$[0]" for the first one;
+// ontlogin's ClientResponse/ClientResponseJWT don't nest credentials inside
+// a JSON-LD VP the way the Presentation Exchange spec's "$.verifiableCredential[0]"
+// examples assume, so Path is always relative to that flat list.
+type DescriptorMap struct {
+	ID     string `json:"id"`
+	Format string `json:"format,omitempty"`
+	Path   string `json:"path"`
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package sdk
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ontology-tech/ontlogin-sdk-go/modules"
+	"github.com/ontology-tech/ontlogin-sdk-go/sdk/noncestore"
+)
+
+// longFormIonDid builds a did:ion long-form identifier (short form plus a
+// base64url-encoded create operation in its ":initial-state" suffix) whose
+// single key is pub, mirroring what did/ion.Resolver reconstructs a
+// Document from. It has more than two colons, which is what trips up a
+// naive 3-segment DID split.
+func longFormIonDid(t *testing.T, pub ed25519.PublicKey) (holderDid, verificationMethod string) {
+	t.Helper()
+	op := struct {
+		Delta struct {
+			Patches []struct {
+				Action     string `json:"action"`
+				PublicKeys []struct {
+					ID           string                 `json:"id"`
+					Type         string                 `json:"type"`
+					PublicKeyJwk map[string]interface{} `json:"publicKeyJwk,omitempty"`
+					Purposes     []string               `json:"purposes,omitempty"`
+				} `json:"publicKeys,omitempty"`
+			} `json:"patches"`
+		} `json:"delta"`
+	}{}
+	op.Delta.Patches = []struct {
+		Action     string `json:"action"`
+		PublicKeys []struct {
+			ID           string                 `json:"id"`
+			Type         string                 `json:"type"`
+			PublicKeyJwk map[string]interface{} `json:"publicKeyJwk,omitempty"`
+			Purposes     []string               `json:"purposes,omitempty"`
+		} `json:"publicKeys,omitempty"`
+	}{{
+		Action: "add-public-keys",
+		PublicKeys: []struct {
+			ID           string                 `json:"id"`
+			Type         string                 `json:"type"`
+			PublicKeyJwk map[string]interface{} `json:"publicKeyJwk,omitempty"`
+			Purposes     []string               `json:"purposes,omitempty"`
+		}{{
+			ID:   "key-1",
+			Type: "Ed25519VerificationKey2020",
+			PublicKeyJwk: map[string]interface{}{
+				"kty": "OKP",
+				"crv": "Ed25519",
+				"x":   base64.RawURLEncoding.EncodeToString(pub),
+			},
+			Purposes: []string{"authentication"},
+		}},
+	}}
+	stateJSON, err := json.Marshal(op)
+	if err != nil {
+		t.Fatalf("marshal create operation: %v", err)
+	}
+	shortForm := "did:ion:EiTestShortForm"
+	holderDid = shortForm + ":" + base64.RawURLEncoding.EncodeToString(stateJSON)
+	return holderDid, holderDid + "#key-1"
+}
+
+// TestValidateClientResponseAcceptsDidIonHolder drives an actual did:ion
+// long-form holder through ValidateClientResponse end to end. Its DID has
+// more than two colons, so this guards against GetDIDChain rejecting it
+// before signature verification ever runs.
+func TestValidateClientResponseAcceptsDidIonHolder(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	holderDid, verificationMethod := longFormIonDid(t, pub)
+
+	s, err := NewOntLoginSdkWithNonceStore(&SDKConfig{
+		Chain:      []string{"ion"},
+		Alg:        []string{"EdDSA"},
+		ServerInfo: &modules.ServerInfo{Name: "test-server", Url: "https://example.com", Did: "did:ion:server"},
+	}, nil, noncestore.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewOntLoginSdkWithNonceStore: %v", err)
+	}
+
+	nonce, err := s.nonceStore.Issue(modules.ACTION_AUTHORIZATION, time.Minute)
+	if err != nil {
+		t.Fatalf("issue nonce: %v", err)
+	}
+
+	msg := &modules.ClientResponseMsg{
+		Type: modules.TYPE_CLIENT_RESPONSE,
+		Server: modules.ServerInfoToSign{
+			Name: "test-server",
+			Url:  "https://example.com",
+			Did:  "did:ion:server",
+		},
+		Nonce: nonce,
+		Did:   holderDid,
+	}
+	dataToSign, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal message: %v", err)
+	}
+
+	res := &modules.ClientResponse{
+		Ver:   modules.SYS_VER,
+		Type:  modules.TYPE_CLIENT_RESPONSE,
+		Did:   holderDid,
+		Nonce: nonce,
+		Proof: &modules.Proof{
+			VerificationMethod: verificationMethod,
+			Value:              hex.EncodeToString(ed25519.Sign(priv, dataToSign)),
+		},
+	}
+
+	if err := s.ValidateClientResponse(res); err != nil {
+		t.Fatalf("ValidateClientResponse: %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package sdk
+
+import "github.com/ontology-tech/ontlogin-sdk-go/modules"
+
+// ServerInfo returns the SDKConfig.ServerInfo OntLoginSdk was configured
+// with, so front-ends built on top of it (e.g. sdk/oidc) can sign with the
+// same server DID without duplicating SDKConfig.
+func (s *OntLoginSdk) ServerInfo() *modules.ServerInfo {
+	return s.conf.ServerInfo
+}
+
+// Alg returns the signature algorithms OntLoginSdk was configured to accept,
+// in preference order.
+func (s *OntLoginSdk) Alg() []string {
+	return s.conf.Alg
+}
@@ -0,0 +1,356 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package sdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ontology-tech/ontlogin-sdk-go/did"
+	"github.com/ontology-tech/ontlogin-sdk-go/modules"
+	"github.com/ontology-tech/ontlogin-sdk-go/sdk/noncestore"
+)
+
+// GenerateChallengeJWT is the JWT/JWS counterpart of GenerateChallenge: it
+// builds the same server-hello payload but returns it as a signed compact
+// JWT, so wallets that already speak VC-JWT can consume it without learning
+// ontlogin's JSON envelope.
+func (s *OntLoginSdk) GenerateChallengeJWT(req *modules.ClientHello) (string, error) {
+	hello, err := s.GenerateChallenge(req)
+	if err != nil {
+		return "", err
+	}
+
+	chain, err := s.GetDIDChain(s.conf.ServerInfo.Did)
+	if err != nil {
+		return "", err
+	}
+	processor, ok := s.didProcessors[chain]
+	if !ok {
+		return "", fmt.Errorf("not a support did chain:%s", chain)
+	}
+	_, index, err := getDIDKeyAndIndex(s.conf.ServerInfo.VerificationMethod)
+	if err != nil {
+		return "", err
+	}
+
+	header := modules.JOSEHeader{Alg: s.conf.Alg[0], Kid: s.conf.ServerInfo.VerificationMethod, Typ: "JWT"}
+	signingInput, err := encodeSigningInput(header, hello)
+	if err != nil {
+		return "", err
+	}
+	sig, err := processor.Sign(s.conf.ServerInfo.Did, index, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// ValidateClientResponseJWT is the JWT/JWS counterpart of
+// ValidateClientResponse: it accepts a compact client-response JWT (vc/vp
+// claim, iss = holder DID, aud = server DID, nonce = server-issued nonce)
+// instead of the JSON ClientResponse envelope, and verifies it and any
+// embedded VC/VP JWTs against the holder DID before enforcing VCFilters.
+func (s *OntLoginSdk) ValidateClientResponseJWT(token string) error {
+	header, claims, signingInput, sig, err := decodeJWT(token, &modules.ClientResponseJWT{})
+	if err != nil {
+		return err
+	}
+	if err = s.requireAcceptedAlg(header.Alg); err != nil {
+		return err
+	}
+	resp := claims.(*modules.ClientResponseJWT)
+	if err = validateTimeBounds(resp.Nbf, resp.Exp); err != nil {
+		return err
+	}
+
+	holderDid, pubKey, err := resolveVerificationKey(s.resolvers, header.Kid)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(holderDid, resp.Iss) {
+		return fmt.Errorf("did and iss not match")
+	}
+	if !strings.EqualFold(resp.Aud, s.conf.ServerInfo.Did) {
+		return fmt.Errorf("aud does not match server did")
+	}
+	// See ValidateClientResponse: Peek, not Consume, so an attacker who
+	// observed this nonce can't burn it with a forged token ahead of the
+	// legitimate holder's real one.
+	action, err := s.nonceStore.Peek(resp.Nonce)
+	if err != nil {
+		if errors.Is(err, noncestore.ErrNonceReplayed) {
+			return noncestore.ErrNonceReplayed
+		}
+		return fmt.Errorf("peek nonce failed:%s", err.Error())
+	}
+	if err = did.VerifySignature(pubKey, []byte(signingInput), sig); err != nil {
+		return err
+	}
+
+	requiredTypes := s.conf.VCFilters[action]
+	definition := s.conf.PresentationDefinitions[action]
+	if resp.VP == nil {
+		// A server that configured VCFilters/a PresentationDefinition for
+		// this action requires a credential; a response that omits vp
+		// entirely must not be treated as satisfying that requirement.
+		if len(requiredTypes) > 0 || definition != nil {
+			return fmt.Errorf("a verifiable presentation is required for this action")
+		}
+	} else {
+		subjects := make([]map[string]interface{}, 0, len(resp.VP.VerifiableCredential))
+		for _, credToken := range resp.VP.VerifiableCredential {
+			subject, err := s.verifyCredentialJWT(holderDid, credToken, requiredTypes)
+			if err != nil {
+				return err
+			}
+			subjects = append(subjects, subject)
+		}
+		if definition != nil {
+			if err = evaluatePresentationSubmission(definition, resp.PresentationSubmission, subjects); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err = s.nonceStore.Consume(resp.Nonce); err != nil {
+		if errors.Is(err, noncestore.ErrNonceReplayed) {
+			return noncestore.ErrNonceReplayed
+		}
+		return fmt.Errorf("consume nonce failed:%s", err.Error())
+	}
+	return nil
+}
+
+// verifyCredentialJWT verifies a single embedded VC-JWT was issued by
+// holderDid, enforces requiredTypes against its declared vc type, and
+// returns its credentialSubject so callers can also evaluate it against a
+// PresentationDefinition. A credToken containing "~" is an SD-JWT combined
+// presentation rather than a plain compact JWT and is routed to
+// verifySelectiveDisclosureJWT instead.
+func (s *OntLoginSdk) verifyCredentialJWT(holderDid, credToken string, requiredTypes []*modules.VCFilter) (map[string]interface{}, error) {
+	if strings.Contains(credToken, "~") {
+		return s.verifySelectiveDisclosureJWT(holderDid, credToken, requiredTypes)
+	}
+
+	credHeader, credClaims, signingInput, sig, err := decodeJWT(credToken, &modules.CredentialJWT{})
+	if err != nil {
+		return nil, err
+	}
+	if err = s.requireAcceptedAlg(credHeader.Alg); err != nil {
+		return nil, err
+	}
+	cred := credClaims.(*modules.CredentialJWT)
+	if err = validateTimeBounds(cred.Nbf, cred.Exp); err != nil {
+		return nil, err
+	}
+
+	credDid, pubKey, err := resolveVerificationKey(s.resolvers, credHeader.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(credDid, holderDid) || !strings.EqualFold(cred.Iss, holderDid) {
+		return nil, fmt.Errorf("credential was not issued to the holder did")
+	}
+	if err = did.VerifySignature(pubKey, []byte(signingInput), sig); err != nil {
+		return nil, err
+	}
+	if err = satisfiesVCFilters(cred.VC.Type, requiredTypes); err != nil {
+		return nil, err
+	}
+	return cred.VC.CredentialSubject, nil
+}
+
+// verifySelectiveDisclosureJWT verifies an SD-JWT credential presented in
+// combined format (an issuer-signed CredentialJWT plus a "~"-joined
+// selection of disclosures): the issuer JWT is verified exactly like a
+// plain CredentialJWT, then every disclosed claim is checked against the
+// "_sd" digest array its credentialSubject carries before being merged in,
+// so any PresentationDefinition field constraint sees the disclosed
+// attributes without ever requiring the full credential; requiredTypes is
+// checked against the credential's declared vc type.
+func (s *OntLoginSdk) verifySelectiveDisclosureJWT(holderDid, credToken string, requiredTypes []*modules.VCFilter) (map[string]interface{}, error) {
+	jwt, disclosures, err := parseSDJWT(credToken)
+	if err != nil {
+		return nil, err
+	}
+
+	credHeader, credClaims, signingInput, sig, err := decodeJWT(jwt, &modules.CredentialJWT{})
+	if err != nil {
+		return nil, err
+	}
+	if err = s.requireAcceptedAlg(credHeader.Alg); err != nil {
+		return nil, err
+	}
+	cred := credClaims.(*modules.CredentialJWT)
+	if err = validateTimeBounds(cred.Nbf, cred.Exp); err != nil {
+		return nil, err
+	}
+
+	credDid, pubKey, err := resolveVerificationKey(s.resolvers, credHeader.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(credDid, holderDid) || !strings.EqualFold(cred.Iss, holderDid) {
+		return nil, fmt.Errorf("credential was not issued to the holder did")
+	}
+	if err = did.VerifySignature(pubKey, []byte(signingInput), sig); err != nil {
+		return nil, err
+	}
+
+	sdDigests, _ := cred.VC.CredentialSubject["_sd"].([]interface{})
+	disclosed, err := resolveDisclosedClaims(sdDigests, disclosures)
+	if err != nil {
+		return nil, err
+	}
+	delete(cred.VC.CredentialSubject, "_sd")
+	for claim, value := range disclosed {
+		cred.VC.CredentialSubject[claim] = value
+	}
+
+	if err = satisfiesVCFilters(cred.VC.Type, requiredTypes); err != nil {
+		return nil, err
+	}
+	return cred.VC.CredentialSubject, nil
+}
+
+// satisfiesVCFilters reports whether types (a credential's declared @type
+// array) matches at least one of requiredTypes. An empty requiredTypes
+// admits anything.
+func satisfiesVCFilters(types []string, requiredTypes []*modules.VCFilter) error {
+	if len(requiredTypes) == 0 {
+		return nil
+	}
+	for _, filter := range requiredTypes {
+		if matchesVCType(types, filter.Type) {
+			return nil
+		}
+	}
+	return fmt.Errorf("credentialSubject does not satisfy any VCFilter")
+}
+
+// resolveVerificationKey resolves the did carried in a "did:method:id#fragment"
+// verificationMethod/kid against registry and decodes the matching
+// verificationMethod's embedded key material, returning both the did and
+// the concrete key object VerifySignature expects.
+func resolveVerificationKey(registry *did.Registry, verificationMethod string) (string, interface{}, error) {
+	holderDid, err := did.DidFromVerificationMethod(verificationMethod)
+	if err != nil {
+		return "", nil, err
+	}
+	doc, err := registry.Resolve(holderDid)
+	if err != nil {
+		return "", nil, fmt.Errorf("resolve did failed:%s", err.Error())
+	}
+	vm, err := doc.VerificationMethodByID(verificationMethod)
+	if err != nil {
+		return "", nil, err
+	}
+	pubKey, err := vm.PublicKey()
+	if err != nil {
+		return "", nil, err
+	}
+	return holderDid, pubKey, nil
+}
+
+// matchesVCType reports whether want is among the VC's declared types.
+func matchesVCType(types []string, want string) bool {
+	for _, t := range types {
+		if strings.EqualFold(t, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeSigningInput base64url-encodes header and payload and joins them
+// with the "." separator used as the JWS signing input.
+func encodeSigningInput(header interface{}, payload interface{}) (string, error) {
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(payloadJSON), nil
+}
+
+// decodeJWT splits a compact JWT into its signing input and signature, and
+// unmarshals its header and claims. claims must be a pointer to the target
+// claim type; the same pointer is returned back for convenience.
+func decodeJWT(token string, claims interface{}) (modules.JOSEHeader, interface{}, string, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return modules.JOSEHeader{}, nil, "", nil, fmt.Errorf("jwt format invalid")
+	}
+
+	var header modules.JOSEHeader
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return header, nil, "", nil, fmt.Errorf("decode jwt header failed:%s", err.Error())
+	}
+	if err = json.Unmarshal(headerJSON, &header); err != nil {
+		return header, nil, "", nil, fmt.Errorf("unmarshal jwt header failed:%s", err.Error())
+	}
+
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return header, nil, "", nil, fmt.Errorf("decode jwt payload failed:%s", err.Error())
+	}
+	if err = json.Unmarshal(payloadJSON, claims); err != nil {
+		return header, nil, "", nil, fmt.Errorf("unmarshal jwt payload failed:%s", err.Error())
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return header, nil, "", nil, fmt.Errorf("decode jwt signature failed:%s", err.Error())
+	}
+	return header, claims, parts[0] + "." + parts[1], sig, nil
+}
+
+// requireAcceptedAlg rejects a JWT signed with an alg the server wasn't
+// configured to accept, so SDKConfig.Alg also bounds which algorithms
+// ValidateClientResponseJWT trusts, not just which ones GenerateChallengeJWT
+// signs with.
+func (s *OntLoginSdk) requireAcceptedAlg(alg string) error {
+	for _, accepted := range s.conf.Alg {
+		if strings.EqualFold(alg, accepted) {
+			return nil
+		}
+	}
+	return fmt.Errorf("jwt alg %q is not accepted by this server", alg)
+}
+
+// validateTimeBounds enforces a JWT's nbf/exp claims against the current
+// time; a zero nbf or exp is unset and not checked.
+func validateTimeBounds(nbf, exp int64) error {
+	now := time.Now().Unix()
+	if nbf != 0 && now < nbf {
+		return fmt.Errorf("jwt is not yet valid (nbf=%d)", nbf)
+	}
+	if exp != 0 && now >= exp {
+		return fmt.Errorf("jwt has expired (exp=%d)", exp)
+	}
+	return nil
+}
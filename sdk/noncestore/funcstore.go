@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package noncestore
+
+import (
+	"sync"
+	"time"
+)
+
+// funcStore adapts ontlogin's original (genRandomNonceFunc, getActionByNonce)
+// callback pair to NonceStore, for callers who have not migrated to one of
+// the built-in stores. Issue defers entirely to genRandomNonceFunc (ttl is
+// not enforced); Consume layers a local once-only guard over
+// getActionByNonce so a captured ClientResponse still can't be replayed.
+type funcStore struct {
+	genRandomNonceFunc func(int) string
+	getActionByNonce   func(string) (int, error)
+
+	mu       sync.Mutex
+	consumed map[string]struct{}
+}
+
+// NewFuncStore adapts the legacy callback pair to a NonceStore. It exists so
+// NewOntLoginSdk can keep accepting callbacks without duplicating replay
+// protection; new callers should use MemoryStore, RedisStore or SQLStore
+// directly via NewOntLoginSdkWithNonceStore.
+func NewFuncStore(genRandomNonceFunc func(int) string, getActionByNonce func(string) (int, error)) NonceStore {
+	return &funcStore{
+		genRandomNonceFunc: genRandomNonceFunc,
+		getActionByNonce:   getActionByNonce,
+		consumed:           make(map[string]struct{}),
+	}
+}
+
+func (f *funcStore) Issue(action int, _ time.Duration) (string, error) {
+	return f.genRandomNonceFunc(action), nil
+}
+
+func (f *funcStore) Consume(nonce string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.consumed[nonce]; ok {
+		return 0, ErrNonceReplayed
+	}
+	action, err := f.getActionByNonce(nonce)
+	if err != nil {
+		return 0, err
+	}
+	f.consumed[nonce] = struct{}{}
+	return action, nil
+}
+
+func (f *funcStore) Peek(nonce string) (int, error) {
+	return f.getActionByNonce(nonce)
+}
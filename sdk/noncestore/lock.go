@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package noncestore
+
+import (
+	"sync"
+	"time"
+)
+
+// nonceLocker serializes concurrent Consume calls for the same nonce in
+// front of a shared backend (Redis, SQL), so the loser of a race observes
+// ErrNonceReplayed from the backend's atomic delete instead of racing it
+// needlessly. A lock is assumed abandoned, and is cleared rather than
+// blocking forever, once it is older than staleAfter.
+type nonceLocker struct {
+	mu         sync.Mutex
+	holders    map[string]time.Time
+	staleAfter time.Duration
+}
+
+func newNonceLocker(staleAfter time.Duration) *nonceLocker {
+	return &nonceLocker{holders: make(map[string]time.Time), staleAfter: staleAfter}
+}
+
+// acquire blocks until nonce is free (or its lock is stale), marks it held,
+// and returns a function that releases it.
+func (l *nonceLocker) acquire(nonce string) func() {
+	for {
+		l.mu.Lock()
+		heldSince, held := l.holders[nonce]
+		if !held || time.Since(heldSince) > l.staleAfter {
+			l.holders[nonce] = time.Now()
+			l.mu.Unlock()
+			return func() {
+				l.mu.Lock()
+				delete(l.holders, nonce)
+				l.mu.Unlock()
+			}
+		}
+		l.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+}
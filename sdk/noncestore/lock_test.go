@@ -0,0 +1,75 @@
+package noncestore
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNonceLockerSerializesSameNonce(t *testing.T) {
+	locker := newNonceLocker(time.Minute)
+
+	release := locker.acquire("n1")
+	acquired := make(chan struct{})
+	go func() {
+		release2 := locker.acquire("n1")
+		close(acquired)
+		release2()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second acquire should have blocked while the first holder was active")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second acquire did not unblock after release")
+	}
+}
+
+func TestNonceLockerStaleLockIsCleared(t *testing.T) {
+	locker := newNonceLocker(10 * time.Millisecond)
+	release := locker.acquire("n1")
+	_ = release // simulate a holder that never released its lock
+
+	time.Sleep(20 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		locker.acquire("n1")()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("acquire did not clear the stale lock")
+	}
+}
+
+func TestNonceLockerIndependentNonces(t *testing.T) {
+	locker := newNonceLocker(time.Minute)
+	var wg sync.WaitGroup
+	for _, nonce := range []string{"a", "b", "c"} {
+		wg.Add(1)
+		go func(n string) {
+			defer wg.Done()
+			locker.acquire(n)()
+		}(nonce)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("independent nonces should not contend with each other")
+	}
+}
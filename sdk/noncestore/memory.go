@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package noncestore
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process NonceStore backed by a map. It is a fine
+// default for a single server instance or for tests; a deployment running
+// more than one ontlogin instance behind a load balancer should use
+// RedisStore or SQLStore instead so a nonce is consumed exactly once across
+// all of them.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	action    int
+	expiresAt time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+func (m *MemoryStore) Issue(action int, ttl time.Duration) (string, error) {
+	nonce, err := randomNonce()
+	if err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	m.entries[nonce] = memoryEntry{action: action, expiresAt: time.Now().Add(ttl)}
+	m.mu.Unlock()
+	return nonce, nil
+}
+
+func (m *MemoryStore) Consume(nonce string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[nonce]
+	delete(m.entries, nonce)
+	if !ok || time.Now().After(entry.expiresAt) {
+		return 0, ErrNonceReplayed
+	}
+	return entry.action, nil
+}
+
+func (m *MemoryStore) Peek(nonce string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[nonce]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return 0, ErrNonceReplayed
+	}
+	return entry.action, nil
+}
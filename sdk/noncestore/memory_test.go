@@ -0,0 +1,70 @@
+package noncestore
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreConsumeOnce(t *testing.T) {
+	store := NewMemoryStore()
+	nonce, err := store.Issue(1, time.Minute)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	action, err := store.Consume(nonce)
+	if err != nil {
+		t.Fatalf("consume: %v", err)
+	}
+	if action != 1 {
+		t.Fatalf("expected action 1, got %d", action)
+	}
+
+	if _, err = store.Consume(nonce); !errors.Is(err, ErrNonceReplayed) {
+		t.Fatalf("expected ErrNonceReplayed on replay, got %v", err)
+	}
+}
+
+func TestMemoryStorePeekDoesNotConsume(t *testing.T) {
+	store := NewMemoryStore()
+	nonce, err := store.Issue(2, time.Minute)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	if action, err := store.Peek(nonce); err != nil || action != 2 {
+		t.Fatalf("peek: action=%d err=%v", action, err)
+	}
+
+	action, err := store.Consume(nonce)
+	if err != nil {
+		t.Fatalf("consume after peek: %v", err)
+	}
+	if action != 2 {
+		t.Fatalf("expected action 2, got %d", action)
+	}
+}
+
+func TestMemoryStoreExpires(t *testing.T) {
+	store := NewMemoryStore()
+	nonce, err := store.Issue(1, time.Millisecond)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err = store.Consume(nonce); !errors.Is(err, ErrNonceReplayed) {
+		t.Fatalf("expected ErrNonceReplayed for expired nonce, got %v", err)
+	}
+}
+
+func TestMemoryStoreUnknownNonce(t *testing.T) {
+	store := NewMemoryStore()
+	if _, err := store.Consume("does-not-exist"); !errors.Is(err, ErrNonceReplayed) {
+		t.Fatalf("expected ErrNonceReplayed for unknown nonce, got %v", err)
+	}
+	if _, err := store.Peek("does-not-exist"); !errors.Is(err, ErrNonceReplayed) {
+		t.Fatalf("expected ErrNonceReplayed for unknown nonce, got %v", err)
+	}
+}
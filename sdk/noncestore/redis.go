@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package noncestore
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisKeyPrefix namespaces ontlogin's nonce keys from the rest of whatever
+// Redis database the caller points RedisStore at.
+const redisKeyPrefix = "ontlogin:nonce:"
+
+// RedisStore is a NonceStore backed by Redis, safe for multiple ontlogin
+// instances to share: Consume uses GETDEL so only one caller ever observes
+// a given nonce and every other caller sees ErrNonceReplayed.
+type RedisStore struct {
+	client *redis.Client
+	locker *nonceLocker
+}
+
+// NewRedisStore returns a RedisStore using client. client's connection
+// lifecycle is the caller's responsibility.
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client, locker: newNonceLocker(5 * time.Second)}
+}
+
+func (r *RedisStore) key(nonce string) string {
+	return redisKeyPrefix + nonce
+}
+
+func (r *RedisStore) Issue(action int, ttl time.Duration) (string, error) {
+	nonce, err := randomNonce()
+	if err != nil {
+		return "", err
+	}
+	ctx := context.Background()
+	if err := r.client.Set(ctx, r.key(nonce), strconv.Itoa(action), ttl).Err(); err != nil {
+		return "", err
+	}
+	return nonce, nil
+}
+
+func (r *RedisStore) Consume(nonce string) (int, error) {
+	unlock := r.locker.acquire(nonce)
+	defer unlock()
+
+	val, err := r.client.GetDel(context.Background(), r.key(nonce)).Result()
+	if err == redis.Nil {
+		return 0, ErrNonceReplayed
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(val)
+}
+
+func (r *RedisStore) Peek(nonce string) (int, error) {
+	val, err := r.client.Get(context.Background(), r.key(nonce)).Result()
+	if err == redis.Nil {
+		return 0, ErrNonceReplayed
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(val)
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package noncestore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const defaultSQLTable = "ontlogin_nonces"
+
+// SQLStore is a NonceStore backed by a SQL table, safe for multiple
+// ontlogin instances sharing one database. Consume issues
+// "DELETE ... RETURNING action" so only one caller ever observes the row;
+// every other caller sees ErrNonceReplayed. The expected schema is:
+//
+//	CREATE TABLE ontlogin_nonces (
+//		nonce      TEXT PRIMARY KEY,
+//		action     INTEGER NOT NULL,
+//		expires_at TIMESTAMPTZ NOT NULL
+//	);
+type SQLStore struct {
+	db     *sql.DB
+	table  string
+	locker *nonceLocker
+}
+
+// NewSQLStore returns a SQLStore using db. If table is empty it defaults to
+// "ontlogin_nonces"; the table is expected to already exist (see SQLStore's
+// doc comment for its schema).
+func NewSQLStore(db *sql.DB, table string) *SQLStore {
+	if table == "" {
+		table = defaultSQLTable
+	}
+	return &SQLStore{db: db, table: table, locker: newNonceLocker(5 * time.Second)}
+}
+
+func (s *SQLStore) Issue(action int, ttl time.Duration) (string, error) {
+	nonce, err := randomNonce()
+	if err != nil {
+		return "", err
+	}
+	_, err = s.db.Exec(
+		`INSERT INTO `+s.table+` (nonce, action, expires_at) VALUES ($1, $2, $3)`,
+		nonce, action, time.Now().Add(ttl),
+	)
+	if err != nil {
+		return "", err
+	}
+	return nonce, nil
+}
+
+func (s *SQLStore) Consume(nonce string) (int, error) {
+	unlock := s.locker.acquire(nonce)
+	defer unlock()
+
+	var action int
+	row := s.db.QueryRow(
+		`DELETE FROM `+s.table+` WHERE nonce = $1 AND expires_at > now() RETURNING action`,
+		nonce,
+	)
+	if err := row.Scan(&action); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNonceReplayed
+		}
+		return 0, err
+	}
+	return action, nil
+}
+
+func (s *SQLStore) Peek(nonce string) (int, error) {
+	var action int
+	row := s.db.QueryRow(
+		`SELECT action FROM `+s.table+` WHERE nonce = $1 AND expires_at > now()`,
+		nonce,
+	)
+	if err := row.Scan(&action); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNonceReplayed
+		}
+		return 0, err
+	}
+	return action, nil
+}
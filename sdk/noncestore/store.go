@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package noncestore provides the NonceStore OntLoginSdk uses to issue
+// server-hello nonces and consume them exactly once, so a captured
+// ClientResponse cannot be replayed. MemoryStore is suitable for a single
+// server instance; RedisStore and SQLStore let multiple instances share one
+// backend safely.
+package noncestore
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrNonceReplayed is returned by Consume when nonce has already been
+// consumed, has expired, or was never issued, so callers can distinguish a
+// replay attempt from any other lookup failure.
+var ErrNonceReplayed = errors.New("noncestore: nonce already consumed or unknown")
+
+// NonceStore issues and consumes the nonces ontlogin's challenge/response
+// handshake is built on. Consume must be atomic: of two concurrent calls
+// for the same nonce, exactly one may succeed.
+type NonceStore interface {
+	// Issue generates and persists a new nonce bound to action, valid for ttl.
+	Issue(action int, ttl time.Duration) (nonce string, err error)
+	// Consume atomically deletes nonce and returns the action it was issued
+	// for, or ErrNonceReplayed if nonce is not present.
+	Consume(nonce string) (action int, err error)
+	// Peek returns the action nonce was issued for without consuming it.
+	Peek(nonce string) (action int, err error)
+}
+
+// randomNonce returns a random hex-encoded nonce, used by every built-in
+// NonceStore implementation.
+func randomNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+)
+
+// discoveryDocument is the subset of the OIDC discovery document ontlogin
+// publishes at /.well-known/openid-configuration.
+type discoveryDocument struct {
+	Issuer                           string   `json:"issuer"`
+	AuthorizationEndpoint            string   `json:"authorization_endpoint"`
+	TokenEndpoint                    string   `json:"token_endpoint"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	ResponseTypesSupported           []string `json:"response_types_supported"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// Discovery serves /.well-known/openid-configuration.
+func (p *Provider) Discovery(w http.ResponseWriter, r *http.Request) {
+	doc := discoveryDocument{
+		Issuer:                           p.conf.Issuer,
+		AuthorizationEndpoint:            p.conf.Issuer + "/authorize",
+		TokenEndpoint:                    p.conf.Issuer + "/token",
+		JWKSURI:                          p.conf.Issuer + "/.well-known/jwks.json",
+		ResponseTypesSupported:           []string{"code"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: p.sdk.Alg(),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(doc)
+}
+
+// jwk is a single JSON Web Key, covering the EC and OKP key types ontlogin's
+// DID processors hand back from DidProcessor.PublicKey.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	Use string `json:"use,omitempty"`
+}
+
+// JWKS serves /.well-known/jwks.json, publishing the public key for the
+// server DID's signing verificationMethod.
+func (p *Provider) JWKS(w http.ResponseWriter, r *http.Request) {
+	did, keyIndex, err := splitVerificationMethod(p.signingVerificationMethod())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pub, err := p.processor.PublicKey(did, keyIndex)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	key, err := toJWK(p.signingVerificationMethod(), pub)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Keys []jwk `json:"keys"`
+	}{Keys: []jwk{key}})
+}
+
+// toJWK converts a public key returned by DidProcessor.PublicKey into its
+// JWK representation.
+func toJWK(kid string, pub interface{}) (jwk, error) {
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		byteLen := (k.Curve.Params().BitSize + 7) / 8
+		return jwk{
+			Kty: "EC",
+			Kid: kid,
+			Crv: k.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(padBigInt(k.X, byteLen)),
+			Y:   base64.RawURLEncoding.EncodeToString(padBigInt(k.Y, byteLen)),
+			Use: "sig",
+		}, nil
+	case ed25519.PublicKey:
+		return jwk{
+			Kty: "OKP",
+			Kid: kid,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(k),
+			Use: "sig",
+		}, nil
+	default:
+		return jwk{}, fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+// padBigInt left-pads b's bytes to size, as required for JWK EC coordinates.
+func padBigInt(b *big.Int, size int) []byte {
+	out := make([]byte, size)
+	b.FillBytes(out)
+	return out
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ontology-tech/ontlogin-sdk-go/modules"
+)
+
+// isAllowedRedirectURI reports whether uri exactly matches a registered
+// entry of Config.AllowedRedirectURIs. Authorize must reject anything else:
+// redirecting to an unregistered URI is an open redirect that lets an
+// attacker exfiltrate the authorization code Callback later mints.
+func (p *Provider) isAllowedRedirectURI(uri string) bool {
+	for _, allowed := range p.conf.AllowedRedirectURIs {
+		if uri == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize implements the OAuth2 authorization endpoint. It starts an
+// ontlogin challenge, remembers the relying party's redirect_uri/state/nonce
+// against the resulting ontlogin nonce, and redirects the browser to the
+// wallet deep link so the user can approve the login in their wallet app.
+func (p *Provider) Authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI := q.Get("redirect_uri")
+	if redirectURI == "" {
+		http.Error(w, "redirect_uri is required", http.StatusBadRequest)
+		return
+	}
+	if !p.isAllowedRedirectURI(redirectURI) {
+		http.Error(w, "redirect_uri is not registered", http.StatusBadRequest)
+		return
+	}
+
+	hello, err := p.sdk.GenerateChallenge(&modules.ClientHello{
+		Ver:    modules.SYS_VER,
+		Type:   modules.TYPE_CLIENT_HELLO,
+		Action: modules.ACTION_AUTHORIZATION,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	p.mu.Lock()
+	p.pending[hello.Nonce] = &authRequest{
+		redirectURI: redirectURI,
+		state:       q.Get("state"),
+		nonce:       q.Get("nonce"),
+	}
+	p.mu.Unlock()
+
+	walletURL := fmt.Sprintf("%s?nonce=%s", p.conf.WalletURIScheme, url.QueryEscape(hello.Nonce))
+	http.Redirect(w, r, walletURL, http.StatusFound)
+}
+
+// Callback is where the wallet POSTs its ClientResponse after the user
+// approves the login. On success it mints an authorization code and
+// redirects the original browser (via the relying party's redirect_uri) to
+// complete the OAuth2 flow.
+func (p *Provider) Callback(w http.ResponseWriter, r *http.Request) {
+	var resp modules.ClientResponse
+	if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+		http.Error(w, "invalid client response: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	req, ok := p.pending[resp.Nonce]
+	if ok {
+		delete(p.pending, resp.Nonce)
+	}
+	p.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown or expired nonce", http.StatusBadRequest)
+		return
+	}
+
+	if err := p.sdk.ValidateClientResponse(&resp); err != nil {
+		http.Error(w, "validate client response: "+err.Error(), http.StatusForbidden)
+		return
+	}
+
+	chain, err := p.sdk.GetDIDChain(resp.Did)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var vcSubjects []string
+	for _, vp := range resp.VPs {
+		jsons, err := p.sdk.GetCredentailJson(chain, vp)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		vcSubjects = append(vcSubjects, jsons...)
+	}
+
+	code := newOpaqueToken()
+	p.mu.Lock()
+	p.codes[code] = &codeGrant{
+		did:       resp.Did,
+		nonce:     req.nonce,
+		vcSubject: vcSubjects,
+		expiresAt: time.Now().Add(p.conf.CodeTTL),
+	}
+	p.mu.Unlock()
+
+	redirectURL, err := url.Parse(req.redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	query := redirectURL.Query()
+	query.Set("code", code)
+	query.Set("state", req.state)
+	redirectURL.RawQuery = query.Encode()
+	http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+}
+
+// tokenResponse is the OAuth2 token endpoint response body.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Token implements the OAuth2 token endpoint for the authorization_code
+// grant: it resolves the code minted by Callback, mints an ID token whose
+// sub is the verified holder DID and whose custom claims carry the verified
+// VC subjects, and returns it alongside an opaque access token.
+func (p *Provider) Token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.FormValue("grant_type") != "authorization_code" {
+		http.Error(w, "unsupported grant_type", http.StatusBadRequest)
+		return
+	}
+	code := r.FormValue("code")
+
+	p.mu.Lock()
+	grant, ok := p.codes[code]
+	if ok {
+		delete(p.codes, code)
+	}
+	p.mu.Unlock()
+	if !ok || time.Now().After(grant.expiresAt) {
+		http.Error(w, "invalid or expired code", http.StatusBadRequest)
+		return
+	}
+
+	idToken, err := p.mintIDToken(grant)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(tokenResponse{
+		AccessToken: newOpaqueToken(),
+		TokenType:   "Bearer",
+		IDToken:     idToken,
+		ExpiresIn:   int64(p.conf.IDTokenTTL.Seconds()),
+	})
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package oidc
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ontology-tech/ontlogin-sdk-go/modules"
+)
+
+// idTokenClaims is the OIDC ID token claim set Provider mints: sub is the
+// verified holder DID, nonce is threaded through from the original
+// authorization request, and VCSubjects carries the verified VC subjects
+// GetCredentailJson returned for the presentations the holder submitted.
+type idTokenClaims struct {
+	Iss        string   `json:"iss"`
+	Sub        string   `json:"sub"`
+	Nonce      string   `json:"nonce,omitempty"`
+	Iat        int64    `json:"iat"`
+	Exp        int64    `json:"exp"`
+	VCSubjects []string `json:"vc_subjects,omitempty"`
+}
+
+// mintIDToken signs grant into a compact JWT using the provider's DID key.
+func (p *Provider) mintIDToken(grant *codeGrant) (string, error) {
+	now := time.Now()
+	claims := idTokenClaims{
+		Iss:        p.conf.Issuer,
+		Sub:        grant.did,
+		Nonce:      grant.nonce,
+		Iat:        now.Unix(),
+		Exp:        now.Add(p.conf.IDTokenTTL).Unix(),
+		VCSubjects: grant.vcSubject,
+	}
+
+	signerDid, keyIndex, err := splitVerificationMethod(p.signingVerificationMethod())
+	if err != nil {
+		return "", err
+	}
+
+	header := modules.JOSEHeader{Alg: p.signingAlg(), Kid: p.signingVerificationMethod(), Typ: "JWT"}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	sig, err := p.processor.Sign(signerDid, keyIndex, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// newOpaqueToken returns a random URL-safe token for authorization codes and
+// access tokens, neither of which need to be self-describing.
+func newOpaqueToken() string {
+	buf := make([]byte, 32)
+	_, _ = rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// signingVerificationMethod returns the verificationMethod id used to sign
+// and to populate the `kid` of ID tokens and JWKS entries.
+func (p *Provider) signingVerificationMethod() string {
+	return p.sdk.ServerInfo().VerificationMethod
+}
+
+// signingAlg returns the alg ID tokens are signed with: the first algorithm
+// OntLoginSdk was configured to accept.
+func (p *Provider) signingAlg() string {
+	algs := p.sdk.Alg()
+	if len(algs) == 0 {
+		return ""
+	}
+	return algs[0]
+}
+
+// splitVerificationMethod splits a "did#keys-N" verificationMethod id into
+// its did and key index, the same convention ontlogin uses server-side.
+func splitVerificationMethod(verifymethod string) (string, int, error) {
+	tmpArr := strings.Split(verifymethod, "#")
+	if len(tmpArr) != 2 {
+		return "", 0, fmt.Errorf("verificationMethod format invalid")
+	}
+	keyArr := strings.Split(tmpArr[1], "-")
+	if len(keyArr) != 2 {
+		return "", 0, fmt.Errorf("verificationMethod format invalid")
+	}
+	idx, err := strconv.Atoi(keyArr[1])
+	return tmpArr[0], idx, err
+}
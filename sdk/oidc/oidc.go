@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package oidc wraps sdk.OntLoginSdk as a minimal OpenID Connect provider,
+// so relying parties can integrate through any OIDC client library instead
+// of learning ontlogin's ClientHello/ClientResponse protocol directly. The
+// "login" step of the authorization-code flow is ontlogin's own
+// challenge/response: the browser is redirected to a wallet URI carrying the
+// ontlogin nonce, the wallet POSTs its ClientResponse back to Provider, and
+// on success Provider mints an authorization code the relying party later
+// exchanges for an ID token.
+package oidc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ontology-tech/ontlogin-sdk-go/did"
+	"github.com/ontology-tech/ontlogin-sdk-go/sdk"
+)
+
+// Config configures a Provider.
+type Config struct {
+	// Issuer is the provider's issuer identifier, used as `iss` in ID
+	// tokens and to build the discovery and JWKS URLs.
+	Issuer string
+	// WalletURIScheme is the deep-link scheme the authorization endpoint
+	// redirects browsers to, e.g. "ontlogin://authorize". The ontlogin
+	// nonce is appended as a query parameter.
+	WalletURIScheme string
+	// AllowedRedirectURIs is the allow-list Authorize checks a request's
+	// redirect_uri against; a request naming any other URI is rejected.
+	// Required: an OIDC provider that redirects to an unregistered
+	// redirect_uri is an open redirect and lets an attacker exfiltrate the
+	// authorization code Callback mints.
+	AllowedRedirectURIs []string
+	// CodeTTL bounds how long an authorization code is valid for exchange.
+	// Defaults to 1 minute.
+	CodeTTL time.Duration
+	// IDTokenTTL bounds the validity of minted ID tokens. Defaults to 10 minutes.
+	IDTokenTTL time.Duration
+}
+
+// Provider is a minimal OIDC provider backed by an OntLoginSdk. It serves
+// the discovery document and JWKS, runs the authorization-code flow, and
+// mints ID tokens whose subject is the verified holder DID.
+type Provider struct {
+	sdk       *sdk.OntLoginSdk
+	conf      Config
+	processor did.DidProcessor
+
+	mu sync.Mutex
+	// pending maps an ontlogin nonce to the relying-party request that is
+	// waiting on its wallet callback.
+	pending map[string]*authRequest
+	// codes maps an issued authorization code to the identity it attests.
+	codes map[string]*codeGrant
+}
+
+// authRequest is the relying-party state kept while waiting for the wallet
+// to POST its ClientResponse back to Provider.Callback.
+type authRequest struct {
+	redirectURI string
+	state       string
+	nonce       string // OIDC nonce, threaded through to the ID token
+}
+
+// codeGrant is what an authorization code resolves to once Provider.Token
+// exchanges it.
+type codeGrant struct {
+	did       string
+	nonce     string // OIDC nonce from the original authorization request
+	vcSubject []string
+	expiresAt time.Time
+}
+
+// NewProvider wraps sdk with an OIDC provider front-end. processor is the
+// did.DidProcessor for SDKConfig.ServerInfo.Did's chain; it is used to sign
+// ID tokens and publish the provider's JWKS.
+func NewProvider(conf Config, ontLoginSdk *sdk.OntLoginSdk, processor did.DidProcessor) (*Provider, error) {
+	if conf.Issuer == "" {
+		return nil, fmt.Errorf("oidc: issuer is required")
+	}
+	if len(conf.AllowedRedirectURIs) == 0 {
+		return nil, fmt.Errorf("oidc: at least one AllowedRedirectURIs entry is required")
+	}
+	if conf.CodeTTL == 0 {
+		conf.CodeTTL = time.Minute
+	}
+	if conf.IDTokenTTL == 0 {
+		conf.IDTokenTTL = 10 * time.Minute
+	}
+	return &Provider{
+		sdk:       ontLoginSdk,
+		conf:      conf,
+		pending:   make(map[string]*authRequest),
+		codes:     make(map[string]*codeGrant),
+		processor: processor,
+	}, nil
+}
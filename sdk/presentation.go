@@ -0,0 +1,334 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ontology-tech/ontlogin-sdk-go/did"
+	"github.com/ontology-tech/ontlogin-sdk-go/modules"
+)
+
+// evaluatePresentationDefinition decodes every credential carried by vps
+// (via processor.GetCredentialJsons) and evaluates them against definition
+// and submission. It is the legacy-VP counterpart of
+// ValidateClientResponseJWT's inline evaluation, called from
+// ValidateClientResponse once every vp has passed signature verification.
+func (s *OntLoginSdk) evaluatePresentationDefinition(processor did.DidProcessor, definition *modules.PresentationDefinition, submission *modules.PresentationSubmission, vps []string) error {
+	if definition == nil {
+		return nil
+	}
+
+	var subjects []map[string]interface{}
+	for _, vp := range vps {
+		credentialJSONs, err := processor.GetCredentialJsons(vp)
+		if err != nil {
+			return fmt.Errorf("decode credentials from presentation failed:%s", err.Error())
+		}
+		for _, credentialJSON := range credentialJSONs {
+			var credential struct {
+				CredentialSubject map[string]interface{} `json:"credentialSubject"`
+			}
+			if err := json.Unmarshal([]byte(credentialJSON), &credential); err != nil {
+				return fmt.Errorf("unmarshal credential failed:%s", err.Error())
+			}
+			subjects = append(subjects, credential.CredentialSubject)
+		}
+	}
+	return evaluatePresentationSubmission(definition, submission, subjects)
+}
+
+// evaluatePresentationSubmission matches submission's descriptor_map
+// entries against definition's input descriptors, checks each matched
+// credentialSubject against its descriptor's field constraints, and
+// enforces definition's submission requirements.
+func evaluatePresentationSubmission(definition *modules.PresentationDefinition, submission *modules.PresentationSubmission, credentialSubjects []map[string]interface{}) error {
+	if definition == nil {
+		return nil
+	}
+	if submission == nil {
+		return fmt.Errorf("presentation definition %s requires a presentation submission", definition.ID)
+	}
+
+	descriptorsByID := make(map[string]*modules.InputDescriptor, len(definition.InputDescriptors))
+	for _, d := range definition.InputDescriptors {
+		descriptorsByID[d.ID] = d
+	}
+
+	satisfied := make(map[string]bool, len(submission.DescriptorMap))
+	for _, dm := range submission.DescriptorMap {
+		descriptor, ok := descriptorsByID[dm.ID]
+		if !ok {
+			return fmt.Errorf("presentation submission references unknown input descriptor %q", dm.ID)
+		}
+		index, err := credentialIndexFromPath(dm.Path)
+		if err != nil {
+			return err
+		}
+		if index < 0 || index >= len(credentialSubjects) {
+			return fmt.Errorf("presentation submission path %q for descriptor %q out of range", dm.Path, dm.ID)
+		}
+		if err := evaluateInputDescriptor(descriptor, credentialSubjects[index]); err != nil {
+			return fmt.Errorf("input descriptor %q: %s", dm.ID, err.Error())
+		}
+		satisfied[dm.ID] = true
+	}
+
+	return enforceSubmissionRequirements(definition, satisfied)
+}
+
+// credentialIndexFromPath parses a DescriptorMap.Path of the form "$[N]".
+func credentialIndexFromPath(path string) (int, error) {
+	if !strings.HasPrefix(path, "$[") || !strings.HasSuffix(path, "]") {
+		return 0, fmt.Errorf("unsupported descriptor_map path %q, expected \"$[N]\"", path)
+	}
+	index, err := strconv.Atoi(path[2 : len(path)-1])
+	if err != nil {
+		return 0, fmt.Errorf("unsupported descriptor_map path %q:%s", path, err.Error())
+	}
+	return index, nil
+}
+
+// evaluateInputDescriptor checks every constraint field of descriptor
+// against subject, the decoded credentialSubject it was matched to.
+func evaluateInputDescriptor(descriptor *modules.InputDescriptor, subject map[string]interface{}) error {
+	if descriptor.Constraints == nil {
+		return nil
+	}
+	for _, field := range descriptor.Constraints.Fields {
+		value, found := lookupField(subject, field.Path)
+		if !found {
+			if field.Optional {
+				continue
+			}
+			return fmt.Errorf("required field %v not present", field.Path)
+		}
+		if len(field.Filter) == 0 {
+			continue
+		}
+		ok, err := matchesFilter(value, field.Filter)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("field %v does not satisfy filter", field.Path)
+		}
+	}
+	return nil
+}
+
+// lookupField evaluates each of a Field's candidate JSONPaths against
+// subject in order and returns the first one that resolves, per the
+// Presentation Exchange spec's "path" array semantics.
+func lookupField(subject map[string]interface{}, paths []string) (interface{}, bool) {
+	for _, path := range paths {
+		if value, ok := evalJSONPath(subject, path); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// evalJSONPath evaluates a restricted JSONPath subset against subject: a
+// "$"-rooted, dot-separated member access and "[N]" integer array
+// indexing. It covers the paths Presentation Exchange filters commonly
+// write against a decoded credentialSubject; it is not a general JSONPath
+// engine.
+func evalJSONPath(subject map[string]interface{}, path string) (interface{}, bool) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, false
+	}
+	path = path[1:]
+	var current interface{} = subject
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+			var segment string
+			if end < 0 {
+				segment, path = path, ""
+			} else {
+				segment, path = path[:end], path[end:]
+			}
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = m[segment]; !ok {
+				return nil, false
+			}
+		case '[':
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return nil, false
+			}
+			index, err := strconv.Atoi(path[1:end])
+			if err != nil {
+				return nil, false
+			}
+			path = path[end+1:]
+			arr, ok := current.([]interface{})
+			if !ok || index < 0 || index >= len(arr) {
+				return nil, false
+			}
+			current = arr[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// matchesFilter evaluates the restricted JSON Schema subset Presentation
+// Exchange filters commonly use against value: "type", "const", "enum" and
+// "pattern".
+func matchesFilter(value interface{}, filter json.RawMessage) (bool, error) {
+	var schema struct {
+		Type    string        `json:"type,omitempty"`
+		Const   interface{}   `json:"const,omitempty"`
+		Enum    []interface{} `json:"enum,omitempty"`
+		Pattern string        `json:"pattern,omitempty"`
+	}
+	if err := json.Unmarshal(filter, &schema); err != nil {
+		return false, fmt.Errorf("invalid filter schema:%s", err.Error())
+	}
+
+	if schema.Type != "" && !matchesJSONType(value, schema.Type) {
+		return false, nil
+	}
+	if schema.Const != nil && !reflect.DeepEqual(value, schema.Const) {
+		return false, nil
+	}
+	if len(schema.Enum) > 0 {
+		matched := false
+		for _, candidate := range schema.Enum {
+			if reflect.DeepEqual(value, candidate) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	if schema.Pattern != "" {
+		str, ok := value.(string)
+		if !ok {
+			return false, nil
+		}
+		re, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			return false, fmt.Errorf("invalid filter pattern:%s", err.Error())
+		}
+		if !re.MatchString(str) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchesJSONType reports whether value decodes to JSON type jsonType. An
+// unrecognized jsonType matches anything, so an unknown schema keyword
+// doesn't reject a credential outright.
+func matchesJSONType(value interface{}, jsonType string) bool {
+	switch jsonType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	default:
+		return true
+	}
+}
+
+// enforceSubmissionRequirements checks definition's submission_requirements
+// against the set of input descriptor ids satisfied. With no
+// submission_requirements, every input descriptor must be satisfied.
+func enforceSubmissionRequirements(definition *modules.PresentationDefinition, satisfied map[string]bool) error {
+	if len(definition.SubmissionRequirements) == 0 {
+		for _, d := range definition.InputDescriptors {
+			if !satisfied[d.ID] {
+				return fmt.Errorf("input descriptor %q was not satisfied", d.ID)
+			}
+		}
+		return nil
+	}
+
+	groups := make(map[string][]string)
+	for _, d := range definition.InputDescriptors {
+		for _, g := range d.Group {
+			groups[g] = append(groups[g], d.ID)
+		}
+	}
+	for _, req := range definition.SubmissionRequirements {
+		if err := enforceSubmissionRequirement(req, groups, satisfied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enforceSubmissionRequirement checks a single submission requirement's
+// "all"/"pick" rule against how many of its From group were satisfied.
+func enforceSubmissionRequirement(req *modules.SubmissionRequirement, groups map[string][]string, satisfied map[string]bool) error {
+	members := groups[req.From]
+	count := 0
+	for _, id := range members {
+		if satisfied[id] {
+			count++
+		}
+	}
+
+	switch req.Rule {
+	case "all":
+		if count != len(members) {
+			return fmt.Errorf("submission requirement %q: all %d descriptors in group %q must be satisfied, got %d", req.Name, len(members), req.From, count)
+		}
+	case "pick":
+		min, max := req.Min, req.Max
+		if req.Count > 0 {
+			min, max = req.Count, req.Count
+		}
+		if min > 0 && count < min {
+			return fmt.Errorf("submission requirement %q: at least %d descriptors from group %q required, got %d", req.Name, min, req.From, count)
+		}
+		if max > 0 && count > max {
+			return fmt.Errorf("submission requirement %q: at most %d descriptors from group %q allowed, got %d", req.Name, max, req.From, count)
+		}
+	default:
+		return fmt.Errorf("submission requirement %q: unsupported rule %q", req.Name, req.Rule)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package sdk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ontology-tech/ontlogin-sdk-go/modules"
+)
+
+func TestEvalJSONPath(t *testing.T) {
+	subject := map[string]interface{}{
+		"degree": map[string]interface{}{
+			"type": "BachelorDegree",
+		},
+		"grades": []interface{}{"A", "B"},
+	}
+
+	if v, ok := evalJSONPath(subject, "$.degree.type"); !ok || v != "BachelorDegree" {
+		t.Fatalf("expected BachelorDegree, got %v ok=%v", v, ok)
+	}
+	if v, ok := evalJSONPath(subject, "$.grades[1]"); !ok || v != "B" {
+		t.Fatalf("expected B, got %v ok=%v", v, ok)
+	}
+	if _, ok := evalJSONPath(subject, "$.missing"); ok {
+		t.Fatalf("expected missing path to fail")
+	}
+	if _, ok := evalJSONPath(subject, "degree.type"); ok {
+		t.Fatalf("expected non-$-rooted path to fail")
+	}
+}
+
+func TestMatchesFilter(t *testing.T) {
+	cases := []struct {
+		name   string
+		value  interface{}
+		filter string
+		want   bool
+	}{
+		{"type match", "BachelorDegree", `{"type":"string"}`, true},
+		{"type mismatch", 42.0, `{"type":"string"}`, false},
+		{"const match", "gold", `{"const":"gold"}`, true},
+		{"enum match", "silver", `{"enum":["gold","silver"]}`, true},
+		{"enum mismatch", "bronze", `{"enum":["gold","silver"]}`, false},
+		{"pattern match", "ABC123", `{"pattern":"^[A-Z]+[0-9]+$"}`, true},
+		{"pattern mismatch", "abc", `{"pattern":"^[A-Z]+[0-9]+$"}`, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := matchesFilter(tc.value, json.RawMessage(tc.filter))
+			if err != nil {
+				t.Fatalf("matchesFilter: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCredentialIndexFromPath(t *testing.T) {
+	if idx, err := credentialIndexFromPath("$[0]"); err != nil || idx != 0 {
+		t.Fatalf("expected index 0, got %d err=%v", idx, err)
+	}
+	if idx, err := credentialIndexFromPath("$[2]"); err != nil || idx != 2 {
+		t.Fatalf("expected index 2, got %d err=%v", idx, err)
+	}
+	if _, err := credentialIndexFromPath("$.verifiableCredential[0]"); err == nil {
+		t.Fatalf("expected unsupported path to error")
+	}
+}
+
+func TestEvaluatePresentationSubmission(t *testing.T) {
+	def := &modules.PresentationDefinition{
+		ID: "pd-1",
+		InputDescriptors: []*modules.InputDescriptor{
+			{
+				ID: "degree",
+				Constraints: &modules.Constraints{
+					Fields: []*modules.Field{
+						{Path: []string{"$.type"}, Filter: json.RawMessage(`{"const":"BachelorDegree"}`)},
+					},
+				},
+			},
+		},
+	}
+	submission := &modules.PresentationSubmission{
+		ID:           "ps-1",
+		DefinitionID: "pd-1",
+		DescriptorMap: []*modules.DescriptorMap{
+			{ID: "degree", Path: "$[0]"},
+		},
+	}
+	subjects := []map[string]interface{}{
+		{"type": "BachelorDegree"},
+	}
+
+	if err := evaluatePresentationSubmission(def, submission, subjects); err != nil {
+		t.Fatalf("expected submission to satisfy definition, got %v", err)
+	}
+
+	if err := evaluatePresentationSubmission(def, nil, subjects); err == nil {
+		t.Fatalf("expected missing submission to error")
+	}
+
+	badSubmission := &modules.PresentationSubmission{
+		ID:           "ps-1",
+		DefinitionID: "pd-1",
+		DescriptorMap: []*modules.DescriptorMap{
+			{ID: "unknown", Path: "$[0]"},
+		},
+	}
+	if err := evaluatePresentationSubmission(def, badSubmission, subjects); err == nil {
+		t.Fatalf("expected unknown descriptor id to error")
+	}
+}
+
+func TestEnforceSubmissionRequirement(t *testing.T) {
+	groups := map[string][]string{"A": {"d1", "d2"}}
+
+	allReq := &modules.SubmissionRequirement{Name: "all-req", Rule: "all", From: "A"}
+	if err := enforceSubmissionRequirement(allReq, groups, map[string]bool{"d1": true, "d2": true}); err != nil {
+		t.Fatalf("expected all-satisfied group to pass, got %v", err)
+	}
+	if err := enforceSubmissionRequirement(allReq, groups, map[string]bool{"d1": true}); err == nil {
+		t.Fatalf("expected partially-satisfied all-rule to fail")
+	}
+
+	pickReq := &modules.SubmissionRequirement{Name: "pick-req", Rule: "pick", From: "A", Count: 1}
+	if err := enforceSubmissionRequirement(pickReq, groups, map[string]bool{"d1": true}); err != nil {
+		t.Fatalf("expected pick count satisfied, got %v", err)
+	}
+	if err := enforceSubmissionRequirement(pickReq, groups, map[string]bool{}); err == nil {
+		t.Fatalf("expected unsatisfied pick-rule to fail")
+	}
+
+	unknownReq := &modules.SubmissionRequirement{Name: "bad-req", Rule: "majority", From: "A"}
+	if err := enforceSubmissionRequirement(unknownReq, groups, map[string]bool{"d1": true, "d2": true}); err == nil {
+		t.Fatalf("expected unsupported rule to fail")
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package sdk
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// sdDisclosure is a single IETF SD-JWT disclosure: the salted [salt,
+// claimName, claimValue] tuple a holder reveals for one selectively
+// disclosed claim.
+type sdDisclosure struct {
+	encoded    string
+	claimName  string
+	claimValue interface{}
+}
+
+// parseSDJWT splits a combined SD-JWT presentation ("<issuer-jwt>~<disclosure>~...")
+// into the issuer-signed CredentialJWT and the disclosures the holder chose
+// to reveal, per draft-ietf-oauth-selective-disclosure-jwt.
+func parseSDJWT(token string) (jwt string, disclosures []sdDisclosure, err error) {
+	parts := strings.Split(token, "~")
+	jwt = parts[0]
+	for _, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		d, err := decodeSDDisclosure(part)
+		if err != nil {
+			return "", nil, err
+		}
+		disclosures = append(disclosures, d)
+	}
+	return jwt, disclosures, nil
+}
+
+// decodeSDDisclosure base64url-decodes a single disclosure and parses its
+// [salt, claimName, claimValue] JSON tuple.
+func decodeSDDisclosure(encoded string) (sdDisclosure, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return sdDisclosure{}, fmt.Errorf("decode sd-jwt disclosure failed:%s", err.Error())
+	}
+	var tuple []interface{}
+	if err := json.Unmarshal(raw, &tuple); err != nil || len(tuple) != 3 {
+		return sdDisclosure{}, fmt.Errorf("sd-jwt disclosure must be a [salt, claimName, claimValue] array")
+	}
+	claimName, ok := tuple[1].(string)
+	if !ok {
+		return sdDisclosure{}, fmt.Errorf("sd-jwt disclosure claim name must be a string")
+	}
+	return sdDisclosure{encoded: encoded, claimName: claimName, claimValue: tuple[2]}, nil
+}
+
+// disclosureDigest returns the base64url sha-256 digest of a disclosure, as
+// compared against the credential's "_sd" digest array.
+func disclosureDigest(d sdDisclosure) string {
+	sum := sha256.Sum256([]byte(d.encoded))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// resolveDisclosedClaims verifies every disclosure digests to an entry of
+// sdDigests (the issuer-signed credential's "_sd" claim) and returns the
+// claims it discloses. A disclosure whose digest is absent from sdDigests
+// was not committed to by the issuer and is rejected; claims the holder
+// chose not to disclose are simply absent rather than failing
+// verification.
+func resolveDisclosedClaims(sdDigests []interface{}, disclosures []sdDisclosure) (map[string]interface{}, error) {
+	digestSet := make(map[string]bool, len(sdDigests))
+	for _, digest := range sdDigests {
+		if s, ok := digest.(string); ok {
+			digestSet[s] = true
+		}
+	}
+
+	claims := make(map[string]interface{}, len(disclosures))
+	for _, d := range disclosures {
+		if !digestSet[disclosureDigest(d)] {
+			return nil, fmt.Errorf("sd-jwt disclosure for %q was not committed to by the issuer", d.claimName)
+		}
+		claims[d.claimName] = d.claimValue
+	}
+	return claims, nil
+}
@@ -0,0 +1,61 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func encodeDisclosure(t *testing.T, salt, claimName string, claimValue interface{}) string {
+	t.Helper()
+	raw, err := json.Marshal([]interface{}{salt, claimName, claimValue})
+	if err != nil {
+		t.Fatalf("marshal disclosure: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func TestParseSDJWT(t *testing.T) {
+	d1 := encodeDisclosure(t, "salt1", "given_name", "Alice")
+	d2 := encodeDisclosure(t, "salt2", "age", 30.0)
+	token := "issuer-jwt~" + d1 + "~" + d2 + "~"
+
+	jwt, disclosures, err := parseSDJWT(token)
+	if err != nil {
+		t.Fatalf("parseSDJWT: %v", err)
+	}
+	if jwt != "issuer-jwt" {
+		t.Fatalf("expected issuer-jwt, got %q", jwt)
+	}
+	if len(disclosures) != 2 {
+		t.Fatalf("expected 2 disclosures, got %d", len(disclosures))
+	}
+	if disclosures[0].claimName != "given_name" || disclosures[1].claimName != "age" {
+		t.Fatalf("unexpected disclosure claim names: %+v", disclosures)
+	}
+}
+
+func TestDecodeSDDisclosureRejectsMalformedTuple(t *testing.T) {
+	bad := base64.RawURLEncoding.EncodeToString([]byte(`["salt","only-two"]`))
+	if _, err := decodeSDDisclosure(bad); err == nil {
+		t.Fatalf("expected malformed tuple to error")
+	}
+}
+
+func TestResolveDisclosedClaims(t *testing.T) {
+	d1 := encodeDisclosure(t, "salt1", "given_name", "Alice")
+	disclosures := []sdDisclosure{{encoded: d1, claimName: "given_name", claimValue: "Alice"}}
+	sdDigests := []interface{}{disclosureDigest(disclosures[0])}
+
+	claims, err := resolveDisclosedClaims(sdDigests, disclosures)
+	if err != nil {
+		t.Fatalf("resolveDisclosedClaims: %v", err)
+	}
+	if claims["given_name"] != "Alice" {
+		t.Fatalf("expected given_name=Alice, got %v", claims["given_name"])
+	}
+
+	if _, err := resolveDisclosedClaims(nil, disclosures); err == nil {
+		t.Fatalf("expected disclosure not committed to by issuer to error")
+	}
+}
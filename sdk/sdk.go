@@ -20,45 +20,123 @@ package sdk
 import (
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ontology-tech/ontlogin-sdk-go/did"
+	"github.com/ontology-tech/ontlogin-sdk-go/did/ion"
+	"github.com/ontology-tech/ontlogin-sdk-go/did/key"
+	"github.com/ontology-tech/ontlogin-sdk-go/did/web"
 	"github.com/ontology-tech/ontlogin-sdk-go/modules"
+	"github.com/ontology-tech/ontlogin-sdk-go/sdk/noncestore"
 )
 
+// didWebCacheTTL bounds how long the default did:web resolver caches a
+// fetched document for.
+const didWebCacheTTL = 10 * time.Minute
+
+// defaultNonceTTL is used when SDKConfig.NonceTTL is left at its zero value.
+const defaultNonceTTL = 5 * time.Minute
+
 type SDKConfig struct {
 	Chain      []string
 	Alg        []string
 	ServerInfo *modules.ServerInfo
 	VCFilters  map[int][]*modules.VCFilter
+	// PresentationDefinitions optionally requests, per action, a DIF
+	// Presentation Exchange style credential with specific attribute
+	// constraints instead of VCFilters' type-only matching. GenerateChallenge
+	// embeds the definition for req.Action in ServerHello, and
+	// ValidateClientResponse/ValidateClientResponseJWT evaluate the holder's
+	// PresentationSubmission against it once every vp has verified.
+	PresentationDefinitions map[int]*modules.PresentationDefinition
+	// NonceTTL bounds how long a nonce issued by GenerateChallenge may be
+	// consumed by ValidateClientResponse. Defaults to defaultNonceTTL.
+	NonceTTL time.Duration
 }
 
 type OntLoginSdk struct {
 	didProcessors map[string]did.DidProcessor
+	resolvers     *did.Registry
 	conf          *SDKConfig
-	//this function should generate and save a random nonce with action for client
-	genRandomNonceFunc func(int) string
-	//this function get action by nonce
-	getActionByNonce func(string) (int, error)
+	nonceStore    noncestore.NonceStore
 }
 
-func NewOntLoginSdk(conf *SDKConfig, processors map[string]did.DidProcessor, nonceFunc func(int) string, getActionByNonce func(string) (int, error)) (*OntLoginSdk, error) {
+// NewOntLoginSdkWithNonceStore is the primary constructor: store is
+// responsible for issuing nonces and consuming them exactly once, so a
+// captured ClientResponse cannot be replayed. See sdk/noncestore for the
+// in-memory, Redis and SQL implementations.
+//
+// Every entry in processors is also registered as a did.Resolver for its
+// chain, and did:key, did:web and did:ion are registered as resolvable
+// methods by default (without overriding an explicit entry of the same
+// name in processors), so holders are not required to use an ont-chain
+// DID. Use Resolvers to register further methods or replace a default.
+func NewOntLoginSdkWithNonceStore(conf *SDKConfig, processors map[string]did.DidProcessor, store noncestore.NonceStore) (*OntLoginSdk, error) {
+	if conf.NonceTTL == 0 {
+		conf.NonceTTL = defaultNonceTTL
+	}
+
+	resolvers := did.NewRegistry()
+	for chain, processor := range processors {
+		resolvers.Register(chain, processor)
+	}
+	registerDefaultResolvers(resolvers)
+
 	return &OntLoginSdk{
-		didProcessors:      processors,
-		conf:               conf,
-		genRandomNonceFunc: nonceFunc,
-		getActionByNonce:   getActionByNonce,
+		didProcessors: processors,
+		resolvers:     resolvers,
+		conf:          conf,
+		nonceStore:    store,
 	}, nil
 }
 
-func (s *OntLoginSdk) GetDIDChain(did string) (string, error) {
-	tmpArr := strings.Split(did, ":")
-	if len(tmpArr) != 3 {
+// registerDefaultResolvers registers the resolvers that need no chain
+// processor: did:key resolves entirely offline, did:web fetches over
+// HTTPS, and did:ion (sidetree long-form) is reconstructed from its
+// ":initial-state" suffix without contacting a node. An entry already
+// present in resolvers (e.g. a caller-supplied DidProcessor for that
+// method) is left untouched.
+func registerDefaultResolvers(resolvers *did.Registry) {
+	for method, resolver := range map[string]did.Resolver{
+		"key": key.NewResolver(),
+		"web": web.NewResolver(nil, didWebCacheTTL),
+		"ion": ion.NewResolver(),
+	} {
+		if _, ok := resolvers.Lookup(method); !ok {
+			resolvers.Register(method, resolver)
+		}
+	}
+}
+
+// Resolvers returns the did.Registry OntLoginSdk resolves verificationMethods
+// against, so callers can register additional DID methods or replace a
+// default resolver.
+func (s *OntLoginSdk) Resolvers() *did.Registry {
+	return s.resolvers
+}
+
+// NewOntLoginSdk is kept for backwards compatibility with callers that
+// manage nonces themselves via a pair of callbacks. It adapts them to a
+// NonceStore; prefer NewOntLoginSdkWithNonceStore for replay protection that
+// is safe across multiple server instances.
+func NewOntLoginSdk(conf *SDKConfig, processors map[string]did.DidProcessor, nonceFunc func(int) string, getActionByNonce func(string) (int, error)) (*OntLoginSdk, error) {
+	return NewOntLoginSdkWithNonceStore(conf, processors, noncestore.NewFuncStore(nonceFunc, getActionByNonce))
+}
+
+// GetDIDChain extracts the chain (DID method) a holder DID resolves on.
+// It delegates to did.Method rather than splitting on ":" itself, since a
+// did:ion long-form identifier or a path-based did:web identifier carries
+// more than two colons and would otherwise be rejected as malformed.
+func (s *OntLoginSdk) GetDIDChain(holderDid string) (string, error) {
+	method, err := did.Method(holderDid)
+	if err != nil {
 		return "", fmt.Errorf("valid did format")
 	}
-	return tmpArr[1], nil
+	return method, nil
 }
 
 func (s *OntLoginSdk) GenerateChallenge(req *modules.ClientHello) (*modules.ServerHello, error) {
@@ -67,8 +145,11 @@ func (s *OntLoginSdk) GenerateChallenge(req *modules.ClientHello) (*modules.Serv
 	if err := s.validateClientHello(req); err != nil {
 		return nil, err
 	}
-	//2. generate uuid
-	uuid := s.genRandomNonceFunc(req.Action)
+	//2. issue a nonce bound to the requested action
+	uuid, err := s.nonceStore.Issue(req.Action, s.conf.NonceTTL)
+	if err != nil {
+		return nil, fmt.Errorf("issue nonce failed:%s", err.Error())
+	}
 
 	res := &modules.ServerHello{}
 	res.Ver = modules.SYS_VER
@@ -81,6 +162,9 @@ func (s *OntLoginSdk) GenerateChallenge(req *modules.ClientHello) (*modules.Serv
 	if s.conf.VCFilters[req.Action] != nil {
 		res.VCFilters = s.conf.VCFilters[req.Action]
 	}
+	if s.conf.PresentationDefinitions[req.Action] != nil {
+		res.PresentationDefinition = s.conf.PresentationDefinitions[req.Action]
+	}
 	//serverproof
 	//extension
 	return res, nil
@@ -102,17 +186,41 @@ func (s *OntLoginSdk) ValidateClientResponse(res *modules.ClientResponse) error
 		return err
 	}
 
-	did, index, err := getDIDKeyAndIndex(res.Proof.VerificationMethod)
-	if !strings.EqualFold(did, res.Did) {
+	holderDid, err := did.DidFromVerificationMethod(res.Proof.VerificationMethod)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(holderDid, res.Did) {
 		return fmt.Errorf("did and VerificationMethod not match")
 	}
-	chain, err := s.GetDIDChain(did)
+	doc, err := s.resolvers.Resolve(holderDid)
+	if err != nil {
+		return fmt.Errorf("resolve did failed:%s", err.Error())
+	}
+	vm, err := doc.VerificationMethodByID(res.Proof.VerificationMethod)
 	if err != nil {
 		return err
 	}
-	action, err := s.getActionByNonce(res.Nonce)
+	pubKey, err := vm.PublicKey()
 	if err != nil {
-		return fmt.Errorf("nonce is existed on server side")
+		return err
+	}
+
+	chain, err := s.GetDIDChain(holderDid)
+	if err != nil {
+		return err
+	}
+	// The nonce is handed to the client in ServerHello, so it isn't a
+	// secret an attacker couldn't observe. Only Peek its action here, and
+	// Consume it once every check below (signature, presentation) has
+	// passed, so an attacker who replays an observed nonce with a forged
+	// response can't burn it out from under the legitimate holder.
+	action, err := s.nonceStore.Peek(res.Nonce)
+	if err != nil {
+		if errors.Is(err, noncestore.ErrNonceReplayed) {
+			return noncestore.ErrNonceReplayed
+		}
+		return fmt.Errorf("peek nonce failed:%s", err.Error())
 	}
 	msg := &modules.ClientResponseMsg{
 		Type: res.Type,
@@ -122,7 +230,7 @@ func (s *OntLoginSdk) ValidateClientResponse(res *modules.ClientResponse) error
 			Did:  s.conf.ServerInfo.Did,
 		},
 		Nonce:   res.Nonce,
-		Did:     did,
+		Did:     holderDid,
 		Created: res.Proof.Created,
 	}
 
@@ -134,22 +242,72 @@ func (s *OntLoginSdk) ValidateClientResponse(res *modules.ClientResponse) error
 	if err != nil {
 		return fmt.Errorf("marshal message failed:%s", err.Error())
 	}
-	processor, ok := s.didProcessors[chain]
-	if !ok {
-		return fmt.Errorf("not a support did chain:%s", chain)
-	}
-	if err = processor.VerifySig(did, index, dataToSign, sigdata); err != nil {
+	if err = did.VerifySignature(pubKey, dataToSign, sigdata); err != nil {
 		return err
 	}
 
 	//verify presentation
-	if res.VPs != nil && len(res.VPs) > 0 {
-		requiredTypes := s.conf.VCFilters[action]
-		for _, vp := range res.VPs {
-			if err = processor.VerifyPresentation(did, index, vp, requiredTypes); err != nil {
-				return err
+	requiredTypes := s.conf.VCFilters[action]
+	definition := s.conf.PresentationDefinitions[action]
+	if len(res.VPs) == 0 {
+		// A server that configured VCFilters/a PresentationDefinition for
+		// this action requires a credential; a response that omits vps
+		// entirely must not be treated as satisfying that requirement.
+		if len(requiredTypes) > 0 || definition != nil {
+			return fmt.Errorf("a verifiable presentation is required for this action")
+		}
+	} else {
+		processor, ok := s.didProcessors[chain]
+		if ok {
+			// VerifyPresentation is chain-specific and still addresses keys
+			// by index rather than by verificationMethod; this falls back
+			// to 0 when holderDid's verificationMethod doesn't use the
+			// "#keys-N" convention the chain itself understands.
+			_, index, err := getDIDKeyAndIndex(res.Proof.VerificationMethod)
+			if err != nil {
+				index = 0
+			}
+			for _, vp := range res.VPs {
+				if err = processor.VerifyPresentation(holderDid, index, vp, requiredTypes); err != nil {
+					return err
+				}
+			}
+			if definition != nil {
+				if err = s.evaluatePresentationDefinition(processor, definition, res.PresentationSubmission, res.VPs); err != nil {
+					return err
+				}
 			}
+		} else {
+			// holderDid's method (did:key, did:web, did:ion, or a caller's
+			// own resolver-only method) has no DidProcessor to dispatch a
+			// chain-specific VerifyPresentation to; fall back to verifying
+			// each vp as a genericCredential array directly against the
+			// did.Registry, so these holders aren't limited to credential-
+			// free logins.
+			var subjects []map[string]interface{}
+			for _, vp := range res.VPs {
+				vpSubjects, err := s.verifyGenericPresentation(vp, requiredTypes)
+				if err != nil {
+					return err
+				}
+				subjects = append(subjects, vpSubjects...)
+			}
+			if definition != nil {
+				if err = evaluatePresentationSubmission(definition, res.PresentationSubmission, subjects); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Only now that the response has passed every check is the nonce
+	// actually consumed, closing the replay hole without handing an
+	// observer-of-the-nonce a free-form denial-of-service primitive.
+	if _, err = s.nonceStore.Consume(res.Nonce); err != nil {
+		if errors.Is(err, noncestore.ErrNonceReplayed) {
+			return noncestore.ErrNonceReplayed
 		}
+		return fmt.Errorf("consume nonce failed:%s", err.Error())
 	}
 	return nil
 }
@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2021 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package sdk
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ontology-tech/ontlogin-sdk-go/did"
+	"github.com/ontology-tech/ontlogin-sdk-go/modules"
+)
+
+// genericCredential is the encoding ValidateClientResponse expects a VP to
+// carry for a holder whose DID method (did:key, did:web, did:ion, or any
+// caller-registered resolver-only method) has no DidProcessor and
+// therefore no chain-specific presentation codec: a flat JSON array of
+// credentials, each signed by its issuer the same way a ClientResponse
+// itself is signed by its holder (Proof.Value a hex signature, resolved
+// against Proof.VerificationMethod via the did.Registry).
+type genericCredential struct {
+	Type              []string        `json:"type"`
+	Issuer            string          `json:"issuer"`
+	CredentialSubject json.RawMessage `json:"credentialSubject"`
+	Proof             *modules.Proof  `json:"proof"`
+}
+
+// verifyGenericPresentation verifies vp as a JSON array of genericCredential
+// against the did.Registry, the fallback OntLoginSdk.ValidateClientResponse
+// takes for a holderDid whose method has no registered DidProcessor to
+// dispatch DidProcessor.VerifyPresentation to. Every credential's issuer
+// proof is verified and its declared Type checked against requiredTypes,
+// exactly as a chain's VerifyPresentation would; the decoded
+// credentialSubjects are returned so a configured PresentationDefinition
+// can also be evaluated against them.
+func (s *OntLoginSdk) verifyGenericPresentation(vp string, requiredTypes []*modules.VCFilter) ([]map[string]interface{}, error) {
+	var credentials []genericCredential
+	if err := json.Unmarshal([]byte(vp), &credentials); err != nil {
+		return nil, fmt.Errorf("decode presentation failed:%s", err.Error())
+	}
+
+	subjects := make([]map[string]interface{}, 0, len(credentials))
+	for _, cred := range credentials {
+		subject, err := s.verifyGenericCredential(cred, requiredTypes)
+		if err != nil {
+			return nil, err
+		}
+		subjects = append(subjects, subject)
+	}
+	return subjects, nil
+}
+
+// verifyGenericCredential verifies a single genericCredential's issuer proof
+// and enforces requiredTypes against its declared Type, returning the
+// decoded credentialSubject.
+func (s *OntLoginSdk) verifyGenericCredential(cred genericCredential, requiredTypes []*modules.VCFilter) (map[string]interface{}, error) {
+	if cred.Proof == nil {
+		return nil, fmt.Errorf("credential has no proof")
+	}
+	issuerDid, pubKey, err := resolveVerificationKey(s.resolvers, cred.Proof.VerificationMethod)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(issuerDid, cred.Issuer) {
+		return nil, fmt.Errorf("credential proof verificationMethod does not match issuer")
+	}
+
+	sigdata, err := hex.DecodeString(cred.Proof.Value)
+	if err != nil {
+		return nil, fmt.Errorf("decode credential proof value failed:%s", err.Error())
+	}
+	unsigned := cred
+	unsigned.Proof = nil
+	dataToSign, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("marshal credential failed:%s", err.Error())
+	}
+	if err = did.VerifySignature(pubKey, dataToSign, sigdata); err != nil {
+		return nil, err
+	}
+	if err = satisfiesVCFilters(cred.Type, requiredTypes); err != nil {
+		return nil, err
+	}
+
+	var subject map[string]interface{}
+	if err = json.Unmarshal(cred.CredentialSubject, &subject); err != nil {
+		return nil, fmt.Errorf("unmarshal credentialSubject failed:%s", err.Error())
+	}
+	return subject, nil
+}